@@ -0,0 +1,87 @@
+package main
+
+import (
+	"os"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	gloss "github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
+
+const (
+	colorModeAuto      = "auto"      // Detect from the terminal, honouring NO_COLOR
+	colorModeTrueColor = "truecolor"
+	colorMode256       = "256"
+	colorMode16        = "16"
+	colorModeNone      = "none"
+
+	lowHealthFraction = 0.3 // Below this the health bar switches to its warning pattern
+)
+
+// applyColorMode forces the color profile chosen in the config. In auto mode lipgloss
+// detects the terminal itself, which already turns colors off when NO_COLOR is set.
+func applyColorMode(mode string) {
+	switch mode {
+	case colorModeTrueColor:
+		gloss.SetColorProfile(termenv.TrueColor)
+	case colorMode256:
+		gloss.SetColorProfile(termenv.ANSI256)
+	case colorMode16:
+		gloss.SetColorProfile(termenv.ANSI)
+	case colorModeNone:
+		gloss.SetColorProfile(termenv.Ascii)
+	default:
+		gloss.SetColorProfile(termenv.NewOutput(os.Stdout).EnvColorProfile())
+	}
+}
+
+// isLowColor reports whether the terminal has too few colors for gradients and color-only cues
+func isLowColor() bool {
+	return gloss.ColorProfile() >= termenv.ANSI // Profiles go from TrueColor down to Ascii
+}
+
+// HealthBar renders a health bar. With few or no colors the fill pattern changes when
+// health is low so the warning doesn't depend on seeing red.
+func (t Theme) HealthBar(percent float64) string {
+	if !t.LowColor {
+		return t.ProgressBar.ViewAs(percent)
+	}
+	width := t.ProgressBar.Width
+	filled := int(percent*float64(width) + 0.5)
+	fill := "="
+	label := ""
+	if percent < lowHealthFraction {
+		fill = "!"
+		label = " LOW"
+	}
+	return "[" + strings.Repeat(fill, filled) + strings.Repeat("-", width-filled) + "]" +
+		gloss.NewStyle().Bold(true).Render(label)
+}
+
+// hitBanner is shown instead of the red damage flash when flashing is off or colors are limited
+func (t Theme) hitBanner() string {
+	return t.ErrorBorder.Bold(true).Render("HIT!")
+}
+
+// animationDelay scales an animation step by the configured animation speed, 0 when animations are off
+func (m *model) animationDelay(base time.Duration) time.Duration {
+	switch m.config.AnimationSpeed {
+	case animationSlow:
+		return base * 2
+	case animationFast:
+		return base / 2
+	case animationOff:
+		return 0
+	default:
+		return base
+	}
+}
+
+// animationTick schedules the next frame of a UI animation
+func (m *model) animationTick() tea.Cmd {
+	return tea.Tick(max(m.animationDelay(50*time.Millisecond), time.Millisecond), func(t time.Time) tea.Msg {
+		return TickMsg(t)
+	})
+}
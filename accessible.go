@@ -0,0 +1,326 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/sss7526/dungeon_crawl/engine"
+)
+
+// textSession runs the game as plain lines of text for screen readers. It never uses
+// the alt screen, colors or cursor movement, and every action is a typed command.
+type textSession struct {
+	m       *model
+	out     io.Writer
+	playing bool
+}
+
+type textCommand struct {
+	name    string
+	usage   string
+	help    string
+	inGame  bool // Only available during a run
+	handler func(s *textSession, args []string) (quit bool)
+}
+
+var textCommands []textCommand
+
+func init() {
+	// Assigned here because the help command lists textCommands itself
+	textCommands = []textCommand{
+		{"help", "", "List commands", false, (*textSession).cmdHelp},
+		{"new", "casual|permadeath|daily", "Start a new run", false, (*textSession).cmdNew},
+		{"saves", "", "List saved games", false, (*textSession).cmdSaves},
+		{"load", "<slot>", "Load a saved game by number or file name", false, (*textSession).cmdLoad},
+		{"look", "", "Describe your condition", true, (*textSession).cmdLook},
+		{"stats", "", "Read your stats", true, (*textSession).cmdStats},
+		{"inventory", "", "List what you carry", true, (*textSession).cmdInventory},
+		{"hit", "", "Take a hit for 10 damage", true, (*textSession).cmdHit},
+		{"rest", "", "Rest to recover 10 health", true, (*textSession).cmdRest},
+		{"use", "<item>", "Use an item you carry", true, (*textSession).cmdUse},
+		{"save", "[slot]", "Save the run, optionally to a named slot", true, (*textSession).cmdSave},
+		{"quit", "", "Leave the game", false, func(*textSession, []string) bool { return true }},
+	}
+}
+
+// runAccessible reads commands from in until quit or end of input. A start command from
+// the command line, such as new casual, runs first and must start a run.
+func runAccessible(m *model, in io.Reader, out io.Writer, start []string) error {
+	s := &textSession{m: m, out: out}
+	s.say("Welcome to the Dungeon. Accessible text mode.")
+	if start != nil {
+		s.dispatch(start[0], start[1:])
+		if !s.playing {
+			return errors.New("the run could not be started")
+		}
+	} else {
+		s.say("Type help for a list of commands, or new casual to start a run.")
+	}
+
+	scanner := bufio.NewScanner(in)
+	for {
+		fmt.Fprint(out, "> ")
+		if !scanner.Scan() {
+			s.say("Goodbye.")
+			return scanner.Err()
+		}
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		if s.dispatch(strings.ToLower(fields[0]), fields[1:]) {
+			s.say("Goodbye.")
+			return nil
+		}
+	}
+}
+
+func (s *textSession) dispatch(name string, args []string) bool {
+	for _, command := range textCommands {
+		if command.name != name {
+			continue
+		}
+		if command.inGame && !s.playing {
+			s.say("You are not in a run. Type new casual to start one, or load a save.")
+			return false
+		}
+		quit := command.handler(s, args)
+		s.announceEvents()
+		return quit
+	}
+	s.say("Unknown command " + name + ". Type help for a list of commands.")
+	return false
+}
+
+// say announces one event as a plain line
+func (s *textSession) say(line string) {
+	fmt.Fprintln(s.out, line)
+}
+
+// announceEvents says what happened in the game since it was last asked
+func (s *textSession) announceEvents() {
+	for _, e := range s.m.pendingEvents {
+		debugEvent(e)
+		if _, _, text, ok := describeEvent(e); ok {
+			if !strings.HasSuffix(text, ".") && !strings.HasSuffix(text, "!") && !strings.HasSuffix(text, "?") {
+				text += "."
+			}
+			s.say(text)
+		}
+	}
+	s.m.pendingEvents = nil
+}
+
+// run executes a command produced by the game and announces the messages it returns
+func (s *textSession) run(cmd tea.Cmd) {
+	if cmd == nil {
+		return
+	}
+	s.announce(cmd())
+}
+
+func (s *textSession) announce(msg tea.Msg) {
+	switch msg := msg.(type) {
+	case logMsg:
+		s.say(msg.text + ".")
+	case error:
+		var gameErr *gameError
+		if errors.As(msg, &gameErr) {
+			s.say(gameErr.category.String() + ": " + gameErr.message + ".")
+			return
+		}
+		s.say("Error: " + msg.Error())
+	case tea.BatchMsg:
+		for _, cmd := range msg {
+			s.run(cmd)
+		}
+	}
+}
+
+func (s *textSession) cmdHelp(_ []string) bool {
+	for _, command := range textCommands {
+		line := command.name
+		if command.usage != "" {
+			line += " " + command.usage
+		}
+		s.say(line + ": " + command.help)
+	}
+	return false
+}
+
+func (s *textSession) cmdNew(args []string) bool {
+	mode, ranked, err := parseMode(strings.Join(args, " "))
+	if err != nil {
+		s.say("Unknown mode " + strings.Join(args, " ") + ". Choose casual, permadeath or daily.")
+		return false
+	}
+	s.m.newGame(mode, ranked)
+	s.playing = true
+	s.say(fmt.Sprintf("A new %s run begins. %s", mode, describeHealth(s.m.game)))
+	return false
+}
+
+func (s *textSession) cmdSaves(_ []string) bool {
+	saves, err := sortedSaves(s.m.store)
+	if err != nil {
+		s.say("Error: " + err.Error())
+		return false
+	}
+	if len(saves) == 0 {
+		s.say("No saved games.")
+		return false
+	}
+	for i, save := range saves {
+		s.say(fmt.Sprintf("Slot %d: saved %s, %s mode, health %.0f.", i+1, save.Timestamp.Format(saveTimestampFmt), save.modeOrDefault(), save.health()))
+	}
+	return false
+}
+
+func (s *textSession) cmdLoad(args []string) bool {
+	if len(args) != 1 {
+		s.say("Say which save to load, for example load 1. Type saves to list them.")
+		return false
+	}
+	name, err := resolveSlot(s.m.store, args[0])
+	if err != nil {
+		s.say("Error: " + err.Error())
+		return false
+	}
+	msg := s.m.loadGameState(name)
+	s.announce(msg)
+	if _, failed := msg.(error); !failed {
+		s.playing = true
+		s.say(describeHealth(s.m.game))
+	}
+	return false
+}
+
+func (s *textSession) cmdLook(_ []string) bool {
+	s.say(describeHealth(s.m.game))
+	s.say(fmt.Sprintf("You carry %d items.", len(s.m.game.Inventory())))
+	return false
+}
+
+func (s *textSession) cmdStats(_ []string) bool {
+	stats := s.m.game.Stats()
+	names := make([]string, 0, len(stats))
+	for name := range stats {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		s.say(fmt.Sprintf("%s %d.", name, stats[name]))
+	}
+	s.say(fmt.Sprintf("Health %.0f of %.0f.", s.m.game.Health(), s.m.game.MaxHealth()))
+	s.say(fmt.Sprintf("Seed %d.", s.m.game.Seed()))
+	return false
+}
+
+func (s *textSession) cmdInventory(_ []string) bool {
+	inventory := s.m.game.Inventory()
+	if len(inventory) == 0 {
+		s.say("Your pack is empty.")
+		return false
+	}
+	names := make([]string, len(inventory))
+	for i, item := range inventory {
+		names[i] = s.m.content.ItemName(item)
+	}
+	s.say("You carry: " + strings.Join(names, ", ") + ".")
+	return false
+}
+
+func (s *textSession) cmdHit(_ []string) bool {
+	outcome, err := s.m.game.Apply(engine.ActionHit)
+	if err != nil {
+		s.say("Error: " + err.Error())
+		return false
+	}
+	s.m.unsaved = true
+	s.announceEvents()
+	if outcome.Died {
+		s.die()
+	}
+	return false
+}
+
+func (s *textSession) cmdRest(_ []string) bool {
+	if _, err := s.m.game.Apply(engine.ActionRest); err != nil {
+		s.say("Error: " + err.Error())
+		return false
+	}
+	s.m.unsaved = true
+	return false
+}
+
+func (s *textSession) cmdUse(args []string) bool {
+	if len(args) == 0 {
+		s.say("Use what? Type inventory to see what you carry.")
+		return false
+	}
+	name := strings.Join(args, " ")
+	for _, item := range s.m.game.Inventory() {
+		if strings.EqualFold(item, name) || strings.EqualFold(s.m.content.ItemName(item), name) {
+			outcome, err := s.m.game.Use(item)
+			if err != nil {
+				s.say("Error: " + err.Error())
+				return false
+			}
+			s.m.unsaved = true
+			s.announceEvents()
+			if outcome.Died {
+				s.die()
+			}
+			return false
+		}
+	}
+	s.say("You aren't carrying " + name + ".")
+	return false
+}
+
+func (s *textSession) cmdSave(args []string) bool {
+	if len(args) == 0 {
+		s.run(s.m.saveGameState())
+		return false
+	}
+	if s.m.mode == modePermadeath {
+		s.say("Permadeath runs keep a single save. Type save without a slot name.")
+		return false
+	}
+	s.run(s.m.saveGameStateAs(slotFileName(strings.Join(args, " "), s.m.config.SaveFormat)))
+	return false
+}
+
+func (s *textSession) die() {
+	s.playing = false
+	if s.m.mode == modePermadeath {
+		s.run(s.m.deleteRunSave())
+		s.say("Your run has ended for good. Type new to start again.")
+		return
+	}
+	if s.m.saveFile != "" {
+		s.say("Type load " + s.m.saveFile + " to reload your last save, or new to start again.")
+		return
+	}
+	s.say("Type new to start again.")
+}
+
+// describeHealth puts the player's health into words
+func describeHealth(game *engine.Game) string {
+	health, maxHealth := game.Health(), game.MaxHealth()
+	switch fraction := game.HealthFraction(); {
+	case fraction >= 1:
+		return "You are unhurt."
+	case fraction >= 0.7:
+		return fmt.Sprintf("You are lightly wounded, health %.0f of %.0f.", health, maxHealth)
+	case fraction >= lowHealthFraction:
+		return fmt.Sprintf("You are wounded, health %.0f of %.0f.", health, maxHealth)
+	default:
+		return fmt.Sprintf("You are badly wounded, health %.0f of %.0f.", health, maxHealth)
+	}
+}
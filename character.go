@@ -3,6 +3,7 @@ package main
 import (
 	"fmt"
 	gloss "github.com/charmbracelet/lipgloss"
+	"github.com/sss7526/dungeon_crawl/engine"
 )
 
 type Renderable interface {
@@ -124,41 +125,55 @@ func newAttribute(current, max int) Attribute {
 	return Attribute{Current: current, Max: max}
 }
 
-func newTestPlayer() *Player {
-	return &Player{
-		Info: CharacterInfo {
-			Name:		"Sman",
-			Class:		"Warrior",
-			Level: 		12,
-			Experience: 5600,
+// attributeMax is the top of the scale attributes are drawn against
+const attributeMax = 20
+
+const defaultPlayerName = "Adventurer"
+
+// newPlayerSheet describes the current run's player for the stats screen, naming
+// their class and items from the content definitions
+func newPlayerSheet(game *engine.Game, content *engine.Content, name string) *Player {
+	stats := game.Stats()
+	attribute := func(name string) Attribute {
+		return newAttribute(stats[name], attributeMax)
+	}
+	class := game.Class()
+	if def, ok := content.Classes[class]; ok {
+		class = def.Name
+	}
+	player := &Player{
+		Info: CharacterInfo{
+			Name:  name,
+			Class: class,
+			Level: 1,
 		},
 		Attributes: Attributes{
-			Strength:	newAttribute(8, 10),
-			Agility:	newAttribute(7, 10),
-			Intelect: 	newAttribute(2, 10),
-			Endurance:	newAttribute(9, 10),
-			Luck:		newAttribute(4, 10),
+			Strength:  attribute("Strength"),
+			Agility:   attribute("Agility"),
+			Intelect:  attribute("Intellect"),
+			Endurance: attribute("Endurance"),
+			Luck:      attribute("Luck"),
 		},
 		Stats: Stats{
-			Health:		newAttribute(85, 100),
-			Mana:		newAttribute(30, 50),
-			Stamina:	newAttribute(40, 50),
-			Damage:		25,
-			Defense: 	10,
-			CritRate:	0.15,
+			Health: newAttribute(int(game.Health()), int(game.MaxHealth())),
 		},
-		Inventory: []string{
-			"Health Potion",
-			"Mana Potion",
-			"Antidote",
-		},
-		Equipment:	Equipment{
-			Weapon:		"Sword of Flame",
-			Armor:		"Darksteel Shield",
-			Trinkets:	[]string{"Ring of Vitality", "Amulet of Luck"},
-		},
-		Gold: 200,
+		Inventory: game.Inventory(),
+	}
+	for _, item := range player.Inventory {
+		def, ok := content.Items[item]
+		switch {
+		case !ok:
+		case def.Kind == "weapon" && player.Equipment.Weapon == "":
+			player.Equipment.Weapon = def.Name
+			player.Stats.Damage += def.Damage
+		case def.Kind == "armor" && player.Equipment.Armor == "":
+			player.Equipment.Armor = def.Name
+			player.Stats.Defense += def.Defense
+		case def.Kind == "trinket":
+			player.Equipment.Trinkets = append(player.Equipment.Trinkets, def.Name)
+		}
 	}
+	return player
 }
 
 func renderKeyValue(theme Theme, key, value string) string {
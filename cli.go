@@ -0,0 +1,277 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"maps"
+	"os"
+	"runtime/debug"
+	"slices"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const usage = `usage: dungeon_crawler [flags] [command]
+
+commands:
+  new [mode]    Start a run straight away, mode is casual, permadeath or daily
+  continue      Resume the most recent save
+  version       Print the version
+  saves         Manage saved games, run "dungeon_crawler saves" for its commands
+
+With no command the game opens at the title screen. Flags may come before or after
+new and continue.
+
+flags:
+`
+
+// version is stamped at build time with -ldflags "-X main.version=v1.2.3"
+var version string
+
+func versionString() string {
+	if version != "" {
+		return version
+	}
+	if info, ok := debug.ReadBuildInfo(); ok && info.Main.Version != "" && info.Main.Version != "(devel)" {
+		return info.Main.Version
+	}
+	return "dev"
+}
+
+// options are the command line flags
+type options struct {
+	accessible  bool
+	seed        *uint64
+	class       string
+	name        string
+	load        string
+	saveDir     string
+	theme       string
+	noAltScreen bool
+	logFile     string
+	logLevel    slog.Level
+}
+
+// parseArgs reads the flags and returns them with the command and its arguments
+func parseArgs(args []string, stderr io.Writer) (options, []string, error) {
+	var opts options
+	fs := flag.NewFlagSet("dungeon_crawler", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprint(stderr, usage)
+		fs.PrintDefaults()
+	}
+	fs.BoolVar(&opts.accessible, "accessible", false, "play in plain text mode for screen readers")
+	fs.Func("seed", "seed for new games, so a run can be replayed", func(value string) error {
+		parsed, err := strconv.ParseUint(value, 10, 64)
+		opts.seed = &parsed
+		return err
+	})
+	fs.StringVar(&opts.class, "class", "", "class new runs start as, such as warrior, rogue or mage")
+	fs.StringVar(&opts.name, "name", "", "your character's name in new runs")
+	fs.StringVar(&opts.load, "load", "", "load the save in this slot, a number from \"saves list\" or a file name")
+	fs.StringVar(&opts.saveDir, "save-dir", "", "folder for saves, settings, mods and custom content")
+	fs.StringVar(&opts.theme, "theme", "", "theme to use this session, leaving the saved setting alone")
+	fs.BoolVar(&opts.noAltScreen, "no-alt-screen", false, "draw in the normal terminal buffer instead of a full screen")
+	fs.StringVar(&opts.logFile, "log-file", "", "append debug logs to this file")
+	fs.TextVar(&opts.logLevel, "log-level", slog.LevelInfo, "least important logs to write: debug, info, warn or error")
+
+	if err := fs.Parse(args); err != nil {
+		return opts, nil, err
+	}
+	command := fs.Args()
+	if len(command) == 0 || command[0] == "saves" {
+		return opts, command, nil // The saves command parses its own arguments
+	}
+	// The other commands accept flags among their arguments too
+	rest := command[1:]
+	command = command[:1]
+	for {
+		if err := fs.Parse(rest); err != nil {
+			return opts, nil, err
+		}
+		if fs.NArg() == 0 {
+			return opts, command, nil
+		}
+		command = append(command, fs.Arg(0))
+		rest = fs.Args()[1:]
+	}
+}
+
+// parseMode reads a run mode as typed on the command line or in text mode
+func parseMode(name string) (mode gameMode, ranked bool, err error) {
+	switch strings.ToLower(name) {
+	case "", "casual":
+		return modeCasual, false, nil
+	case "permadeath":
+		return modePermadeath, false, nil
+	case "daily":
+		return modePermadeath, true, nil
+	default:
+		return "", false, fmt.Errorf("unknown mode %s, choose casual, permadeath or daily", name)
+	}
+}
+
+// run is the whole program, returning the process exit code
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	opts, command, err := parseArgs(args, stderr)
+	switch {
+	case errors.Is(err, flag.ErrHelp):
+		return 0
+	case err != nil:
+		return 2 // The flag package has printed the problem and the usage
+	}
+	saveDirOverride = opts.saveDir
+
+	var name string
+	var commandArgs []string
+	if len(command) > 0 {
+		name, commandArgs = command[0], command[1:]
+	}
+	switch name {
+	case "version":
+		fmt.Fprintln(stdout, "dungeon_crawler", versionString())
+		return 0
+	case "saves":
+		return runSavesCommand(commandArgs, stdout, stderr)
+	case "", "new", "continue":
+	default:
+		fmt.Fprintf(stderr, "unknown command %q\n\n%s", name, usage)
+		return 2
+	}
+	if opts.load != "" && name != "" {
+		fmt.Fprintln(stderr, "--load can't be used with new or continue")
+		return 2
+	}
+
+	logFile, err := setupLogging(opts.logFile, opts.logLevel)
+	if err != nil {
+		fmt.Fprintln(stderr, "Error opening log file:", err)
+		return 1
+	}
+	if logFile != nil {
+		defer logFile.Close()
+	}
+	saveDir, _ := getSaveDir()
+	slog.Info("starting", "version", versionString(), "command", name, "saveDir", saveDir)
+
+	m := initialModel()
+	defer m.store.Close()
+	if err := m.applyOptions(opts); err != nil {
+		fmt.Fprintln(stderr, "Error:", err)
+		return 1
+	}
+	start, err := startCommand(m, name, commandArgs, opts.load)
+	switch {
+	case errors.Is(err, errUsage):
+		fmt.Fprintf(stderr, "%v\n\n%s", err, usage)
+		return 2
+	case err != nil:
+		fmt.Fprintln(stderr, "Error:", err)
+		return 1
+	}
+
+	if opts.accessible || os.Getenv("DUNGEON_CRAWLER_ACCESSIBLE") != "" || m.config.Accessible {
+		if err := runAccessible(m, stdin, stdout, start); err != nil {
+			fmt.Fprintln(stderr, "Error:", err)
+			return 1
+		}
+		return 0
+	}
+
+	if start != nil {
+		if err := m.startRun(start); err != nil {
+			fmt.Fprintln(stderr, "Error:", err)
+			return 1
+		}
+	}
+	var programOpts []tea.ProgramOption
+	if !opts.noAltScreen {
+		programOpts = append(programOpts, tea.WithAltScreen())
+	}
+	if _, err := tea.NewProgram(m, programOpts...).Run(); err != nil {
+		fmt.Fprintln(stderr, "Error:", err)
+		return 1
+	}
+	return 0
+}
+
+// applyOptions checks the flags that depend on the content and settings, and applies them
+func (m *model) applyOptions(opts options) error {
+	if opts.class != "" {
+		if _, ok := m.content.Classes[opts.class]; !ok {
+			classes := slices.Sorted(maps.Keys(m.content.Classes))
+			return fmt.Errorf("unknown class %q, choose from %s", opts.class, strings.Join(classes, ", "))
+		}
+	}
+	if opts.theme != "" {
+		source, err := findTheme(opts.theme, m.mods)
+		if err != nil {
+			return err
+		}
+		m.themeOverride = opts.theme
+		m.applyTheme(source)
+	}
+	m.fixedSeed = opts.seed
+	m.fixedClass = opts.class
+	m.fixedName = opts.name
+	return nil
+}
+
+// startCommand turns the command line into the text mode command that starts the run
+// it asks for, nil to open at the title screen
+func startCommand(m *model, name string, args []string, load string) ([]string, error) {
+	switch {
+	case name == "new":
+		if len(args) > 1 {
+			return nil, fmt.Errorf("%w: new takes at most one mode", errUsage)
+		}
+		mode := strings.Join(args, "")
+		if _, _, err := parseMode(mode); err != nil {
+			return nil, fmt.Errorf("%w: %w", errUsage, err)
+		}
+		return []string{"new", mode}, nil
+	case name == "continue":
+		saves, err := sortedSaves(m.store)
+		if err != nil {
+			return nil, err
+		}
+		if len(saves) == 0 {
+			return nil, errors.New("there are no saved games to continue")
+		}
+		return []string{"load", saves[len(saves)-1].fileName}, nil
+	case load != "":
+		fileName, err := resolveSlot(m.store, load)
+		if err != nil {
+			return nil, err
+		}
+		return []string{"load", fileName}, nil
+	default:
+		return nil, nil
+	}
+}
+
+// startRun starts the run a start command asks for, opening the game on it
+func (m *model) startRun(start []string) error {
+	switch start[0] {
+	case "new":
+		mode, ranked, err := parseMode(start[1])
+		if err != nil {
+			return err
+		}
+		m.newGame(mode, ranked)
+		m.startCmd = m.switchScreen(menuGame)
+	case "load":
+		msg := m.loadGameState(start[1])
+		if err, ok := msg.(error); ok {
+			return err
+		}
+		m.startCmd = tea.Batch(m.switchScreen(menuGame), func() tea.Msg { return msg })
+	}
+	return nil
+}
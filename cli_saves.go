@@ -0,0 +1,252 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"text/tabwriter"
+)
+
+const savesUsage = `usage: dungeon_crawler saves <command> [arguments]
+
+commands:
+  list                   List saved games
+  show <slot>            Print the details of a save
+  export <slot> <file>   Copy a save out to a file
+  import <file>          Copy a save file into the save directory
+  validate               Check every save against the save schema
+
+A slot is either the number shown by "saves list" or the save's file name.
+`
+
+// runSavesCommand handles the "saves" subcommand and returns the process exit code
+func runSavesCommand(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprint(stderr, savesUsage)
+		return 2
+	}
+
+	config, err := loadConfig()
+	if err != nil {
+		fmt.Fprintln(stderr, "Warning: problem reading config:", err)
+	}
+	store, err := newSaveStore(config)
+	if err != nil {
+		fmt.Fprintln(stderr, "Failed to open save storage:", err)
+		return 1
+	}
+	defer store.Close()
+
+	switch args[0] {
+	case "list":
+		err = savesList(store, stdout)
+	case "show":
+		if len(args) != 2 {
+			err = errUsage
+			break
+		}
+		err = savesShow(store, args[1], stdout)
+	case "export":
+		if len(args) != 3 {
+			err = errUsage
+			break
+		}
+		err = savesExport(store, args[1], args[2], stdout)
+	case "import":
+		if len(args) != 2 {
+			err = errUsage
+			break
+		}
+		err = savesImport(store, args[1], config, stdout)
+	case "validate":
+		err = savesValidate(store, stdout)
+	default:
+		err = errUsage
+	}
+
+	switch {
+	case errors.Is(err, errUsage):
+		fmt.Fprint(stderr, savesUsage)
+		return 2
+	case err != nil:
+		fmt.Fprintln(stderr, "Error:", err)
+		return 1
+	}
+	return 0
+}
+
+var errUsage = errors.New("invalid usage")
+
+// sortedSaves returns all readable saves, oldest first, matching the numbering of "saves list"
+func sortedSaves(store SaveStore) ([]GameState, error) {
+	saves, err := listSavedGames(store)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(saves, func(i, j int) bool {
+		return saves[i].Timestamp.Before(saves[j].Timestamp)
+	})
+	return saves, nil
+}
+
+// resolveSlot finds the save file name a slot argument refers to
+func resolveSlot(store SaveStore, slot string) (string, error) {
+	saves, err := sortedSaves(store)
+	if err != nil {
+		return "", err
+	}
+	if n, err := strconv.Atoi(slot); err == nil {
+		if n < 1 || n > len(saves) {
+			return "", fmt.Errorf("no save in slot %d", n)
+		}
+		return saves[n-1].fileName, nil
+	}
+	for _, save := range saves {
+		if save.fileName == slot {
+			return slot, nil
+		}
+	}
+	return "", fmt.Errorf("no save named %q", slot)
+}
+
+func savesList(store SaveStore, out io.Writer) error {
+	saves, err := sortedSaves(store)
+	if err != nil {
+		return err
+	}
+	if len(saves) == 0 {
+		fmt.Fprintln(out, "No saved games.")
+		return nil
+	}
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "SLOT\tSAVED\tMODE\tHEALTH\tFILE")
+	for i, save := range saves {
+		fmt.Fprintf(w, "%d\t%s\t%s\t%.0f\t%s\n", i+1, save.Timestamp.Format(saveTimestampFmt), save.modeOrDefault(), save.health(), save.fileName)
+	}
+	return w.Flush()
+}
+
+func savesShow(store SaveStore, slot string, out io.Writer) error {
+	name, err := resolveSlot(store, slot)
+	if err != nil {
+		return err
+	}
+	data, err := store.Read(name)
+	if err != nil {
+		return err
+	}
+	save, err := decodeGameState(data)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(out, "File:      %s (%s)\n", name, detectSaveFormat(data))
+	fmt.Fprintf(out, "Saved:     %s\n", save.Timestamp.Format(saveTimestampFmt))
+	fmt.Fprintf(out, "Mode:      %s\n", save.modeOrDefault())
+	if status := save.rankStatus(); status != "" {
+		fmt.Fprintf(out, "Ranked:    %s\n", status)
+	}
+	fmt.Fprintf(out, "Seed:      %d\n", save.Seed)
+	fmt.Fprintf(out, "Mods:      %s\n", describeMods(save.Mods))
+	fmt.Fprintf(out, "Health:    %.0f / %.0f\n", save.health(), save.maxHealth())
+
+	fmt.Fprintln(out, "Stats:")
+	values := save.stats()
+	stats := make([]string, 0, len(values))
+	for stat := range values {
+		stats = append(stats, stat)
+	}
+	sort.Strings(stats)
+	for _, stat := range stats {
+		fmt.Fprintf(out, "  %-10s %d\n", stat+":", values[stat])
+	}
+
+	fmt.Fprintln(out, "Inventory:")
+	inventory := save.inventory()
+	if len(inventory) == 0 {
+		fmt.Fprintln(out, "  (empty)")
+	}
+	for _, item := range inventory {
+		fmt.Fprintf(out, "  - %s\n", item)
+	}
+	return nil
+}
+
+func savesExport(store SaveStore, slot, path string, out io.Writer) error {
+	name, err := resolveSlot(store, slot)
+	if err != nil {
+		return err
+	}
+	data, err := store.Read(name)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "Exported %s to %s\n", name, path)
+	return nil
+}
+
+func savesImport(store SaveStore, path string, config Config, out io.Writer) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	save, err := decodeGameState(data)
+	if err != nil {
+		return fmt.Errorf("%s is not a valid save: %w", path, err)
+	}
+	if problems := validateGameState(save); len(problems) > 0 {
+		return fmt.Errorf("%s is not a valid save: %w", path, errors.Join(problems...))
+	}
+
+	name := fmt.Sprintf("%s%d%s", saveFilePrefix, save.Timestamp.Unix(), saveFileExtension(detectSaveFormat(data)))
+	if _, err := store.Read(name); err == nil {
+		return fmt.Errorf("a save named %s already exists", name)
+	}
+	if err := store.Write(name, data); err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "Imported %s as %s\n", path, name)
+	return nil
+}
+
+func savesValidate(store SaveStore, out io.Writer) error {
+	names, err := store.Names()
+	if err != nil {
+		return err
+	}
+	invalid := 0
+	for _, name := range names {
+		var problems []error
+		data, err := store.Read(name)
+		if err == nil {
+			var save GameState
+			if save, err = decodeGameState(data); err == nil {
+				problems = validateGameState(save)
+			}
+		}
+		if err != nil {
+			problems = append(problems, err)
+		}
+
+		if len(problems) == 0 {
+			fmt.Fprintf(out, "ok       %s\n", name)
+			continue
+		}
+		invalid++
+		fmt.Fprintf(out, "invalid  %s\n", name)
+		for _, problem := range problems {
+			fmt.Fprintf(out, "         - %s\n", problem)
+		}
+	}
+	fmt.Fprintf(out, "%d saves checked, %d invalid\n", len(names), invalid)
+	if invalid > 0 {
+		return fmt.Errorf("found %d invalid saves", invalid)
+	}
+	return nil
+}
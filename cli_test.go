@@ -0,0 +1,93 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestParseArgs(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		command []string
+		check   func(options) bool
+	}{
+		{"no arguments", nil, nil, func(o options) bool { return o == options{} }},
+		{"flags before new", []string{"--seed", "5", "--class", "rogue", "new", "daily"}, []string{"new", "daily"},
+			func(o options) bool { return o.seed != nil && *o.seed == 5 && o.class == "rogue" }},
+		{"flags after new", []string{"new", "permadeath", "--name", "Ash", "--no-alt-screen"}, []string{"new", "permadeath"},
+			func(o options) bool { return o.name == "Ash" && o.noAltScreen }},
+		{"flags between new and its mode", []string{"new", "--theme", "solarized", "casual"}, []string{"new", "casual"},
+			func(o options) bool { return o.theme == "solarized" }},
+		{"flags after continue", []string{"continue", "--accessible"}, []string{"continue"},
+			func(o options) bool { return o.accessible }},
+		{"load on its own", []string{"--load", "2"}, nil,
+			func(o options) bool { return o.load == "2" }},
+		{"load with a command", []string{"--load", "2", "new"}, []string{"new"},
+			func(o options) bool { return o.load == "2" }},
+		{"saves keeps its own flags", []string{"--save-dir", "dir", "saves", "show", "-v"}, []string{"saves", "show", "-v"},
+			func(o options) bool { return o.saveDir == "dir" }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts, command, err := parseArgs(tt.args, io.Discard)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(command, tt.command) {
+				t.Errorf("command %q, want %q", command, tt.command)
+			}
+			if !tt.check(opts) {
+				t.Errorf("options %+v don't match the flags %q", opts, tt.args)
+			}
+		})
+	}
+}
+
+func TestRunExitCodes(t *testing.T) {
+	tests := []struct {
+		name   string
+		args   []string
+		code   int
+		stderr string
+	}{
+		{"unknown command", []string{"dance"}, 2, `unknown command "dance"`},
+		{"unknown flag", []string{"--colour", "red"}, 2, "flag provided but not defined"},
+		{"bad seed", []string{"--seed", "many", "new"}, 2, "invalid value"},
+		{"load with new", []string{"--load", "1", "new"}, 2, "--load can't be used with new or continue"},
+		{"load with continue", []string{"continue", "--load", "1"}, 2, "--load can't be used with new or continue"},
+		{"help", []string{"-h"}, 0, "usage:"},
+		{"version", []string{"version"}, 0, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			saveDirOverride = t.TempDir()
+			t.Cleanup(func() { saveDirOverride = "" })
+			var stdout, stderr bytes.Buffer
+			if code := run(tt.args, strings.NewReader(""), &stdout, &stderr); code != tt.code {
+				t.Errorf("exit code %d, want %d, stderr:\n%s", code, tt.code, stderr.String())
+			}
+			if !strings.Contains(stderr.String(), tt.stderr) {
+				t.Errorf("stderr %q, want it to mention %q", stderr.String(), tt.stderr)
+			}
+		})
+	}
+}
+
+func TestRestyleKeepsThemeOverride(t *testing.T) {
+	saveDirOverride = t.TempDir()
+	t.Cleanup(func() { saveDirOverride = "" })
+	m := &model{config: Config{Theme: "monochrome"}, themeOverride: "solarized"}
+	m.restyle()
+	if m.theme.Name != "solarized" {
+		t.Errorf("restyled to %q, want the --theme override solarized", m.theme.Name)
+	}
+	m.themeOverride = ""
+	m.restyle()
+	if m.theme.Name != "monochrome" {
+		t.Errorf("restyled to %q without an override, want the configured monochrome", m.theme.Name)
+	}
+}
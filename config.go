@@ -0,0 +1,132 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+)
+
+const configFileName = "config.json"
+
+// Config holds user configuration read from the save directory
+type Config struct {
+	Storage				string				`json:"storage"`			// Save backend: "file", "memory" or "bolt"
+	SaveFormat			string				`json:"saveFormat"`		// Encoding for new saves: "json", "gzip" or "gob"
+	Keys				map[string][]string	`json:"keys,omitempty"`	// Key overrides by action name
+	Theme				string				`json:"theme"`			// Name of a built-in or user theme
+	ColorMode			string				`json:"colorMode"`		// auto, truecolor, 256, 16 or none
+	NoFlash				bool				`json:"noFlash"`			// Replace the full screen damage flash with a text cue
+	Accessible			bool				`json:"accessible"`		// Start in the plain text mode for screen readers
+	AnimationSpeed		string				`json:"animationSpeed"`	// slow, normal, fast or off
+	AutosaveMinutes		int					`json:"autosaveMinutes"`	// Minutes between autosaves during a run, 0 disables
+	DefaultMode			gameMode			`json:"defaultMode"`		// Game mode selected first when starting a new run
+	MessageLogLength	int					`json:"messageLogLength"`	// Messages kept in the message log
+}
+
+const (
+	animationSlow   = "slow"
+	animationNormal = "normal"
+	animationFast   = "fast"
+	animationOff    = "off"
+)
+
+// Allowed values for settings that are picked from a fixed list
+var (
+	colorModeOptions        = []string{colorModeAuto, colorModeTrueColor, colorMode256, colorMode16, colorModeNone}
+	animationSpeedOptions   = []string{animationSlow, animationNormal, animationFast, animationOff}
+	autosaveMinutesOptions  = []int{0, 1, 5, 10, 15, 30}
+	defaultModeOptions      = []gameMode{modeCasual, modePermadeath}
+	messageLogLengthOptions = []int{50, 100, 200, 500}
+	saveFormatOptions       = []string{formatJSON, formatGzip, formatGob}
+	storageOptions          = []string{storageFile, storageMemory, storageBolt}
+)
+
+func defaultConfig() Config {
+	return Config{
+		Storage:			storageFile,
+		SaveFormat:			formatJSON,
+		Theme:				defaultThemeName,
+		ColorMode:			colorModeAuto,
+		AnimationSpeed:		animationNormal,
+		AutosaveMinutes:	5,
+		DefaultMode:		modeCasual,
+		MessageLogLength:	100,
+	}
+}
+
+// validate checks every setting and replaces invalid values with their defaults,
+// returning a description of each value that was replaced
+func (c *Config) validate() error {
+	defaults := defaultConfig()
+	var problems []error
+	fix := func(name string, valid bool, reset func()) {
+		if !valid {
+			problems = append(problems, fmt.Errorf("invalid %s, using the default", name))
+			reset()
+		}
+	}
+	fix("storage", slices.Contains(storageOptions, c.Storage), func() { c.Storage = defaults.Storage })
+	fix("saveFormat", slices.Contains(saveFormatOptions, c.SaveFormat), func() { c.SaveFormat = defaults.SaveFormat })
+	fix("theme", c.Theme != "", func() { c.Theme = defaults.Theme })
+	fix("colorMode", slices.Contains(colorModeOptions, c.ColorMode), func() { c.ColorMode = defaults.ColorMode })
+	fix("animationSpeed", slices.Contains(animationSpeedOptions, c.AnimationSpeed), func() { c.AnimationSpeed = defaults.AnimationSpeed })
+	fix("autosaveMinutes", c.AutosaveMinutes >= 0, func() { c.AutosaveMinutes = defaults.AutosaveMinutes })
+	fix("defaultMode", slices.Contains(defaultModeOptions, c.DefaultMode), func() { c.DefaultMode = defaults.DefaultMode })
+	fix("messageLogLength", c.MessageLogLength > 0, func() { c.MessageLogLength = defaults.MessageLogLength })
+	return errors.Join(problems...)
+}
+
+// resetPreferences restores every player preference to its default. Storage settings
+// are kept since changing them would hide existing saves.
+func (c *Config) resetPreferences() {
+	defaults := defaultConfig()
+	defaults.Storage = c.Storage
+	defaults.SaveFormat = c.SaveFormat
+	*c = defaults
+}
+
+// getConfigPath returns the path of the config file, next to the saves
+func getConfigPath() (string, error) {
+	saveDir, err := getSaveDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(saveDir, configFileName), nil
+}
+
+// loadConfig reads the config file, falling back to defaults when it doesn't exist
+func loadConfig() (Config, error) {
+	cfg := defaultConfig()
+	path, err := getConfigPath()
+	if err != nil {
+		return cfg, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return cfg, nil
+	}
+	if err != nil {
+		return cfg, err
+	}
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return defaultConfig(), err
+	}
+	return cfg, cfg.validate()
+}
+
+// saveConfig writes the config file
+func saveConfig(cfg Config) error {
+	path, err := getConfigPath()
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(cfg, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o600)
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/sss7526/dungeon_crawl/engine"
+)
+
+const contentDirName = "content"
+
+// userContentDir returns the directory players put content files in to add or replace definitions
+func userContentDir() (string, error) {
+	saveDir, err := getSaveDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(saveDir, contentDirName), nil
+}
+
+// loadContent returns the built-in content with each mod's content files merged on top in
+// load order, then the user's own content files, along with the mods still active. A file
+// that doesn't parse is skipped, and if the merged content doesn't hold together the
+// built-in content is used instead, leaving out every mod that brought content.
+// Either way the problems are returned for the error screen.
+func loadContent(mods []modManifest) (*engine.Content, []modManifest, error) {
+	builtin, err := engine.DefaultContent()
+	if err != nil {
+		return builtin, withoutContent(mods), fmt.Errorf("built-in content: %w", err)
+	}
+
+	dir, err := userContentDir()
+	if err != nil {
+		return builtin, withoutContent(mods), err
+	}
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return builtin, withoutContent(mods), err
+	}
+	sort.Strings(paths)
+
+	content := builtin.Clone()
+	var problems []error
+	merge := func(path, source string) {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			problems = append(problems, err)
+			return
+		}
+		if err := content.Merge(data, source); err != nil {
+			problems = append(problems, err)
+		}
+	}
+	for _, mod := range mods {
+		for _, path := range mod.modFiles(contentDirName) {
+			merge(path, mod.Name+"/"+filepath.Base(path))
+		}
+	}
+	for _, path := range paths {
+		merge(path, filepath.Base(path))
+	}
+	if err := content.Validate(); err != nil {
+		fallback := errors.New("using the built-in content instead, without the mods that change it")
+		return builtin, withoutContent(mods), errors.Join(append(problems, err, fallback)...)
+	}
+	return content, mods, errors.Join(problems...)
+}
+
+// withoutContent returns the mods that have no content files, which stay active when
+// the built-in content has to be used
+func withoutContent(mods []modManifest) []modManifest {
+	var kept []modManifest
+	for _, mod := range mods {
+		if len(mod.modFiles(contentDirName)) == 0 {
+			kept = append(kept, mod)
+		}
+	}
+	return kept
+}
+
+// playerClass returns the class new runs start as, the one chosen with --class if any
+func (m *model) playerClass() engine.ClassDef {
+	if class, ok := m.content.Classes[m.fixedClass]; ok {
+		return class
+	}
+	return m.content.Classes[engine.DefaultClass]
+}
@@ -0,0 +1,192 @@
+package engine
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+)
+
+// Player marks the entity the player controls
+type Player struct{}
+
+// Kind is the content ID an entity was made from, the class for the player
+type Kind string
+
+// Name is what an entity is called in messages
+type Name string
+
+type Health struct {
+	Current float64 `json:"current"`
+	Max     float64 `json:"max"`
+}
+
+// Regen recovers health every turn while the entity is wounded but alive
+type Regen struct {
+	PerTurn float64 `json:"per_turn"`
+}
+
+type Position struct {
+	Depth int `json:"depth"`
+	X     int `json:"x"`
+	Y     int `json:"y"`
+}
+
+type Inventory struct {
+	Items []string `json:"items"`
+}
+
+type Stats struct {
+	Values map[string]int `json:"values"`
+}
+
+// AI marks an entity that acts on its own, naming how it behaves
+type AI struct {
+	Behavior string `json:"behavior"`
+}
+
+// StatusEffect is a temporary condition such as poison, counted down each turn
+type StatusEffect struct {
+	Name  string `json:"name"`
+	Turns int    `json:"turns"`
+}
+
+type StatusEffects struct {
+	Active []StatusEffect `json:"active"`
+}
+
+// EntityState is an entity and all of its components, the form entities take in saves
+type EntityState struct {
+	ID        Entity         `json:"id"`
+	Player    bool           `json:"player,omitempty"`
+	Kind      Kind           `json:"kind,omitempty"`
+	Name      Name           `json:"name,omitempty"`
+	Health    *Health        `json:"health,omitempty"`
+	Regen     *Regen         `json:"regen,omitempty"`
+	Position  *Position      `json:"position,omitempty"`
+	Inventory *Inventory     `json:"inventory,omitempty"`
+	Stats     *Stats         `json:"stats,omitempty"`
+	AI        *AI            `json:"ai,omitempty"`
+	Effects   *StatusEffects `json:"effects,omitempty"`
+}
+
+// legacyItemIDs maps the item names saves held before items had IDs to those IDs
+var legacyItemIDs = map[string]string{"Potion": "potion", "Sword": "sword", "Shield": "shield"}
+
+// MigrateItems returns the items with names from older saves replaced by their IDs
+func MigrateItems(items []string) []string {
+	migrated := slices.Clone(items)
+	for i, item := range migrated {
+		if id, ok := legacyItemIDs[item]; ok {
+			migrated[i] = id
+		}
+	}
+	return migrated
+}
+
+// PlayerState builds the player from the fields saves had before entities existed
+func PlayerState(health float64, inventory []string, stats map[string]int) EntityState {
+	return EntityState{
+		ID:        1,
+		Player:    true,
+		Kind:      DefaultClass,
+		Name:      "you",
+		Health:    &Health{Current: health, Max: MaxHealth},
+		Regen:     &Regen{PerTurn: HealthRegen},
+		Inventory: &Inventory{Items: MigrateItems(inventory)},
+		Stats:     &Stats{Values: stats},
+	}
+}
+
+// saveWorld captures every entity with its components, in ID order
+func saveWorld(w *World) []EntityState {
+	ids := map[Entity]bool{}
+	for _, entities := range [][]Entity{
+		w.Players.Entities(), w.Kinds.Entities(), w.Names.Entities(), w.Healths.Entities(), w.Regens.Entities(),
+		w.Positions.Entities(), w.Inventories.Entities(), w.Stats.Entities(), w.AIs.Entities(),
+		w.Effects.Entities(),
+	} {
+		for _, e := range entities {
+			ids[e] = true
+		}
+	}
+	states := make([]EntityState, 0, len(ids))
+	for _, e := range slices.Sorted(maps.Keys(ids)) {
+		state := EntityState{ID: e, Player: w.Players.Has(e)}
+		if kind, ok := w.Kinds.Get(e); ok {
+			state.Kind = *kind
+		}
+		if name, ok := w.Names.Get(e); ok {
+			state.Name = *name
+		}
+		state.Health = saved(&w.Healths, e)
+		state.Regen = saved(&w.Regens, e)
+		state.Position = saved(&w.Positions, e)
+		if inventory, ok := w.Inventories.Get(e); ok {
+			state.Inventory = &Inventory{Items: slices.Clone(inventory.Items)}
+		}
+		if stats, ok := w.Stats.Get(e); ok {
+			state.Stats = &Stats{Values: maps.Clone(stats.Values)}
+		}
+		state.AI = saved(&w.AIs, e)
+		if effects, ok := w.Effects.Get(e); ok {
+			state.Effects = &StatusEffects{Active: slices.Clone(effects.Active)}
+		}
+		states = append(states, state)
+	}
+	return states
+}
+
+// saved copies a component out of its store, nil if the entity doesn't have one
+func saved[T any](s *Store[T], e Entity) *T {
+	component, ok := s.Get(e)
+	if !ok {
+		return nil
+	}
+	copied := *component
+	return &copied
+}
+
+// loadWorld rebuilds a world from saved entities
+func loadWorld(states []EntityState) (*World, error) {
+	w := NewWorld()
+	seen := map[Entity]bool{}
+	for _, state := range states {
+		if state.ID == 0 || seen[state.ID] {
+			return nil, fmt.Errorf("entity id %d is missing or used twice", state.ID)
+		}
+		seen[state.ID] = true
+		w.next = max(w.next, state.ID+1)
+
+		e := state.ID
+		if state.Player {
+			w.Players.Set(e, Player{})
+		}
+		if state.Kind != "" {
+			w.Kinds.Set(e, state.Kind)
+		}
+		if state.Name != "" {
+			w.Names.Set(e, state.Name)
+		}
+		load(&w.Healths, e, state.Health)
+		load(&w.Regens, e, state.Regen)
+		load(&w.Positions, e, state.Position)
+		if state.Inventory != nil {
+			w.Inventories.Set(e, Inventory{Items: MigrateItems(state.Inventory.Items)})
+		}
+		if state.Stats != nil {
+			w.Stats.Set(e, Stats{Values: maps.Clone(state.Stats.Values)})
+		}
+		load(&w.AIs, e, state.AI)
+		if state.Effects != nil {
+			w.Effects.Set(e, StatusEffects{Active: slices.Clone(state.Effects.Active)})
+		}
+	}
+	return w, nil
+}
+
+// load stores a saved component, if the entity had one
+func load[T any](s *Store[T], e Entity, component *T) {
+	if component != nil {
+		s.Set(e, *component)
+	}
+}
@@ -0,0 +1,61 @@
+package engine
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSaveWorldRoundTrip(t *testing.T) {
+	w := NewWorld()
+	player := w.Spawn()
+	w.Players.Set(player, Player{})
+	w.Kinds.Set(player, "rogue")
+	w.Names.Set(player, "you")
+	w.Healths.Set(player, Health{Current: 42.5, Max: 100})
+	w.Regens.Set(player, Regen{PerTurn: HealthRegen})
+	w.Positions.Set(player, Position{Depth: 2, X: 3, Y: 4})
+	w.Inventories.Set(player, Inventory{Items: []string{"potion", "dagger"}})
+	w.Stats.Set(player, Stats{Values: map[string]int{"Agility": 10}})
+	w.Effects.Set(player, StatusEffects{Active: []StatusEffect{{Name: "poison", Turns: 3}}})
+	w.Spawn() // An entity with no components leaves a gap in the IDs
+	goblin := w.Spawn()
+	w.Kinds.Set(goblin, "goblin")
+	w.Healths.Set(goblin, Health{Current: 12, Max: 12})
+	w.AIs.Set(goblin, AI{Behavior: "chase"})
+
+	saved := saveWorld(w)
+	if len(saved) != 2 || saved[0].ID != player || saved[1].ID != goblin {
+		t.Fatalf("saved %+v, want the player and the goblin in ID order", saved)
+	}
+	loaded, err := loadWorld(saved)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := saveWorld(loaded); !reflect.DeepEqual(got, saved) {
+		t.Errorf("round trip changed the entities:\n got %+v\nwant %+v", got, saved)
+	}
+	if got, ok := loaded.Player(); !ok || got != player {
+		t.Errorf("loaded player is %d, %v, want %d", got, ok, player)
+	}
+	if next := loaded.Spawn(); next != goblin+1 {
+		t.Errorf("first entity spawned after loading is %d, want %d", next, goblin+1)
+	}
+
+	// Saved components are copies, so changing the world afterwards leaves them alone
+	inventory, _ := w.Inventories.Get(player)
+	inventory.Items[0] = "sword"
+	if saved[0].Inventory.Items[0] != "potion" {
+		t.Error("saved inventory shares memory with the world")
+	}
+}
+
+func TestLoadWorldRejectsBadIDs(t *testing.T) {
+	for name, states := range map[string][]EntityState{
+		"missing ID":   {{Player: true}},
+		"duplicate ID": {{ID: 1, Player: true}, {ID: 1, Kind: "goblin"}},
+	} {
+		if _, err := loadWorld(states); err == nil {
+			t.Errorf("%s: loading succeeded, want an error", name)
+		}
+	}
+}
@@ -0,0 +1,356 @@
+package engine
+
+import (
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"math/rand/v2"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// DefaultClass is the class new runs start as when none is chosen
+const DefaultClass = "warrior"
+
+//go:embed content/*.json
+var builtinContent embed.FS
+
+var idPattern = regexp.MustCompile(`^[a-z0-9_]+$`)
+
+// itemKinds are the kinds of item the rules know how to use
+var itemKinds = []string{"potion", "weapon", "armor", "trinket", "scroll"}
+
+// ItemDef describes an item. Saves refer to it by ID, so the name can change freely.
+type ItemDef struct {
+	ID          string  `json:"id"`
+	Name        string  `json:"name"`
+	Kind        string  `json:"kind"`
+	Glyph       string  `json:"glyph"`
+	Damage      int     `json:"damage,omitempty"`
+	Defense     int     `json:"defense,omitempty"`
+	Heal        float64 `json:"heal,omitempty"`
+	Description string  `json:"description,omitempty"`
+	Script      string  `json:"script,omitempty"` // Script run when the item is used
+
+	source string // File the definition came from, for error messages
+}
+
+type MonsterDef struct {
+	ID       string  `json:"id"`
+	Name     string  `json:"name"`
+	Glyph    string  `json:"glyph"`
+	Health   float64 `json:"health"`
+	Damage   int     `json:"damage"`
+	Behavior string  `json:"behavior"`       // How its AI acts
+	Loot     string  `json:"loot,omitempty"` // Loot table rolled when it dies
+	MinDepth int     `json:"minDepth,omitempty"`
+
+	source string
+}
+
+// ClassDef is a starting character: health, stats, kit and spells
+type ClassDef struct {
+	ID     string         `json:"id"`
+	Name   string         `json:"name"`
+	Health float64        `json:"health"`
+	Stats  map[string]int `json:"stats"`
+	Items  []string       `json:"items"`
+	Spells []string       `json:"spells,omitempty"`
+
+	source string
+}
+
+type SpellDef struct {
+	ID          string  `json:"id"`
+	Name        string  `json:"name"`
+	Cost        int     `json:"cost"`
+	Damage      int     `json:"damage,omitempty"`
+	Heal        float64 `json:"heal,omitempty"`
+	Description string  `json:"description,omitempty"`
+
+	source string
+}
+
+// LootTable picks items by weight, rolling a number of times
+type LootTable struct {
+	ID      string      `json:"id"`
+	Rolls   int         `json:"rolls"`
+	Entries []LootEntry `json:"entries"`
+
+	source string
+}
+
+type LootEntry struct {
+	Item   string `json:"item"`
+	Weight int    `json:"weight"`
+}
+
+// Roll picks the table's items, drawing from the given stream so drops are reproducible
+func (t LootTable) Roll(r *rand.Rand) []string {
+	total := 0
+	for _, entry := range t.Entries {
+		total += entry.Weight
+	}
+	var drops []string
+	for range t.Rolls {
+		if total <= 0 {
+			break
+		}
+		pick := r.IntN(total)
+		for _, entry := range t.Entries {
+			if pick -= entry.Weight; pick < 0 {
+				drops = append(drops, entry.Item)
+				break
+			}
+		}
+	}
+	return drops
+}
+
+// DungeonDef is the size of the dungeon, which bounds where the player can be moved.
+// Room and monster counts will join it with a level generator to read them.
+type DungeonDef struct {
+	Depth  int `json:"depth"` // Levels down to the bottom
+	Width  int `json:"width"`
+	Height int `json:"height"`
+
+	source string
+}
+
+// Content is every definition the game knows, by ID
+type Content struct {
+	Items    map[string]ItemDef
+	Monsters map[string]MonsterDef
+	Classes  map[string]ClassDef
+	Spells   map[string]SpellDef
+	Loot     map[string]LootTable
+	Dungeon  DungeonDef
+	Scripts  map[string]ScriptDef
+}
+
+// contentFile is the layout of a content file. A file can hold any mix of sections.
+type contentFile struct {
+	Items    []ItemDef    `json:"items"`
+	Monsters []MonsterDef `json:"monsters"`
+	Classes  []ClassDef   `json:"classes"`
+	Spells   []SpellDef   `json:"spells"`
+	Loot     []LootTable  `json:"loot"`
+	Dungeon  *DungeonDef  `json:"dungeon"` // Replaces the dungeon size as a whole
+	Scripts  []ScriptDef  `json:"scripts"`
+}
+
+func NewContent() *Content {
+	return &Content{
+		Items:    map[string]ItemDef{},
+		Monsters: map[string]MonsterDef{},
+		Classes:  map[string]ClassDef{},
+		Spells:   map[string]SpellDef{},
+		Loot:     map[string]LootTable{},
+		Scripts:  map[string]ScriptDef{},
+	}
+}
+
+// DefaultContent returns the definitions built into the game
+func DefaultContent() (*Content, error) {
+	c := NewContent()
+	entries, err := builtinContent.ReadDir("content")
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		data, err := builtinContent.ReadFile("content/" + entry.Name())
+		if err != nil {
+			return nil, err
+		}
+		if err := c.Merge(data, entry.Name()); err != nil {
+			return nil, err
+		}
+	}
+	return c, c.Validate()
+}
+
+// Clone copies the content so it can be merged into without changing the original
+func (c *Content) Clone() *Content {
+	return &Content{
+		Items:    maps.Clone(c.Items),
+		Monsters: maps.Clone(c.Monsters),
+		Classes:  maps.Clone(c.Classes),
+		Spells:   maps.Clone(c.Spells),
+		Loot:     maps.Clone(c.Loot),
+		Dungeon:  c.Dungeon,
+		Scripts:  maps.Clone(c.Scripts),
+	}
+}
+
+// Merge adds the definitions in a content file, replacing any with the same ID.
+// The file is checked on its own first, so a broken file changes nothing.
+func (c *Content) Merge(data []byte, source string) error {
+	var file contentFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return fmt.Errorf("%s: %w", source, err)
+	}
+	var problems []error
+	ids := map[string]bool{}
+	check := func(kind, id string) {
+		switch {
+		case !idPattern.MatchString(id):
+			problems = append(problems, fmt.Errorf("%s: %s id %q must be lowercase letters, digits and underscores", source, kind, id))
+		case ids[kind+"/"+id]:
+			problems = append(problems, fmt.Errorf("%s: %s %q is defined twice", source, kind, id))
+		}
+		ids[kind+"/"+id] = true
+	}
+	for _, def := range file.Items {
+		check("item", def.ID)
+	}
+	for _, def := range file.Monsters {
+		check("monster", def.ID)
+	}
+	for _, def := range file.Classes {
+		check("class", def.ID)
+	}
+	for _, def := range file.Spells {
+		check("spell", def.ID)
+	}
+	for _, def := range file.Loot {
+		check("loot table", def.ID)
+	}
+	for _, def := range file.Scripts {
+		check("script", def.ID)
+	}
+	if len(problems) > 0 {
+		return errors.Join(problems...)
+	}
+
+	for _, def := range file.Items {
+		def.source = source
+		c.Items[def.ID] = def
+	}
+	for _, def := range file.Monsters {
+		def.source = source
+		c.Monsters[def.ID] = def
+	}
+	for _, def := range file.Classes {
+		def.source = source
+		c.Classes[def.ID] = def
+	}
+	for _, def := range file.Spells {
+		def.source = source
+		c.Spells[def.ID] = def
+	}
+	for _, def := range file.Loot {
+		def.source = source
+		c.Loot[def.ID] = def
+	}
+	for _, def := range file.Scripts {
+		def.source = source
+		c.Scripts[def.ID] = def
+	}
+	if file.Dungeon != nil {
+		c.Dungeon = *file.Dungeon
+		c.Dungeon.source = source
+	}
+	return nil
+}
+
+// Validate checks every definition and the references between them, reporting all problems at once
+func (c *Content) Validate() error {
+	var problems []error
+	report := func(source, kind, id, format string, args ...any) {
+		problems = append(problems, fmt.Errorf("%s: %s %q: %s", source, kind, id, fmt.Sprintf(format, args...)))
+	}
+	for _, id := range slices.Sorted(maps.Keys(c.Items)) {
+		def := c.Items[id]
+		if def.Name == "" {
+			report(def.source, "item", id, "missing name")
+		}
+		if !slices.Contains(itemKinds, def.Kind) {
+			report(def.source, "item", id, "kind %q is not one of %s", def.Kind, strings.Join(itemKinds, ", "))
+		}
+		if script, ok := c.Scripts[def.Script]; def.Script != "" && (!ok || script.On != "use") {
+			report(def.source, "item", id, "script %q isn't a script run on use", def.Script)
+		}
+	}
+	for _, id := range slices.Sorted(maps.Keys(c.Monsters)) {
+		def := c.Monsters[id]
+		if def.Name == "" {
+			report(def.source, "monster", id, "missing name")
+		}
+		if def.Health <= 0 {
+			report(def.source, "monster", id, "health must be above 0")
+		}
+		if _, ok := c.Loot[def.Loot]; def.Loot != "" && !ok {
+			report(def.source, "monster", id, "unknown loot table %q", def.Loot)
+		}
+	}
+	for _, id := range slices.Sorted(maps.Keys(c.Classes)) {
+		def := c.Classes[id]
+		if def.Name == "" {
+			report(def.source, "class", id, "missing name")
+		}
+		if def.Health <= 0 {
+			report(def.source, "class", id, "health must be above 0")
+		}
+		for _, item := range def.Items {
+			if _, ok := c.Items[item]; !ok {
+				report(def.source, "class", id, "unknown item %q", item)
+			}
+		}
+		for _, spell := range def.Spells {
+			if _, ok := c.Spells[spell]; !ok {
+				report(def.source, "class", id, "unknown spell %q", spell)
+			}
+		}
+	}
+	for _, id := range slices.Sorted(maps.Keys(c.Spells)) {
+		def := c.Spells[id]
+		if def.Name == "" {
+			report(def.source, "spell", id, "missing name")
+		}
+		if def.Cost < 0 {
+			report(def.source, "spell", id, "cost can't be negative")
+		}
+	}
+	for _, id := range slices.Sorted(maps.Keys(c.Loot)) {
+		def := c.Loot[id]
+		if len(def.Entries) == 0 {
+			report(def.source, "loot table", id, "has no entries")
+		}
+		for _, entry := range def.Entries {
+			if _, ok := c.Items[entry.Item]; !ok {
+				report(def.source, "loot table", id, "unknown item %q", entry.Item)
+			}
+			if entry.Weight <= 0 {
+				report(def.source, "loot table", id, "weight of %q must be above 0", entry.Item)
+			}
+		}
+	}
+	for _, id := range slices.Sorted(maps.Keys(c.Scripts)) {
+		def := c.Scripts[id]
+		if !slices.Contains(scriptTriggers, def.On) {
+			report(def.source, "script", id, "trigger %q is not one of %s", def.On, strings.Join(scriptTriggers, ", "))
+		}
+		if _, err := def.compile(); err != nil {
+			report(def.source, "script", id, "%v", err)
+		}
+	}
+	if d := c.Dungeon; d.Depth < 1 || d.Width < 20 || d.Height < 10 {
+		problems = append(problems, fmt.Errorf("%s: dungeon: needs depth 1 or more and a map of at least 20x10", d.source))
+	}
+	if _, ok := c.Classes[DefaultClass]; !ok {
+		problems = append(problems, fmt.Errorf("the default class %q is missing", DefaultClass))
+	}
+	return errors.Join(problems...)
+}
+
+// ItemName is the display name for an item ID, or the ID itself for items the
+// content doesn't define, such as those in saves from before items had IDs
+func (c *Content) ItemName(id string) string {
+	if def, ok := c.Items[id]; ok {
+		return def.Name
+	}
+	return id
+}
@@ -0,0 +1,113 @@
+package engine
+
+import (
+	"maps"
+	"slices"
+)
+
+// Entity identifies an actor or item in the world. It has no data of its own, its
+// behavior comes from the components stored against it.
+type Entity uint32
+
+// Store holds one kind of component for every entity that has it
+type Store[T any] struct {
+	items map[Entity]*T
+}
+
+// Set gives an entity the component, replacing any it had
+func (s *Store[T]) Set(e Entity, component T) {
+	if s.items == nil {
+		s.items = map[Entity]*T{}
+	}
+	s.items[e] = &component
+}
+
+// Get returns the entity's component for updating in place
+func (s *Store[T]) Get(e Entity) (*T, bool) {
+	component, ok := s.items[e]
+	return component, ok
+}
+
+func (s *Store[T]) Has(e Entity) bool {
+	_, ok := s.items[e]
+	return ok
+}
+
+func (s *Store[T]) Remove(e Entity) {
+	delete(s.items, e)
+}
+
+// Entities lists every entity with the component in ID order, so systems that roll
+// dice visit entities in the same order on every run
+func (s *Store[T]) Entities() []Entity {
+	return slices.Sorted(maps.Keys(s.items))
+}
+
+// Each calls fn for every entity with the component, in ID order
+func (s *Store[T]) Each(fn func(Entity, *T)) {
+	for _, e := range s.Entities() {
+		fn(e, s.items[e])
+	}
+}
+
+// Join calls fn for every entity that has both components, in ID order
+func Join[A, B any](a *Store[A], b *Store[B], fn func(Entity, *A, *B)) {
+	a.Each(func(e Entity, first *A) {
+		if second, ok := b.Get(e); ok {
+			fn(e, first, second)
+		}
+	})
+}
+
+// World is every entity in a run and their components. Adding a component kind
+// means adding its store here and to EntityState, so it is saved with the rest.
+type World struct {
+	next        Entity
+	Players     Store[Player]
+	Kinds       Store[Kind]
+	Names       Store[Name]
+	Healths     Store[Health]
+	Regens      Store[Regen]
+	Positions   Store[Position]
+	Inventories Store[Inventory]
+	Stats       Store[Stats]
+	AIs         Store[AI]
+	Effects     Store[StatusEffects]
+}
+
+func NewWorld() *World {
+	return &World{next: 1}
+}
+
+// Spawn creates an entity with no components
+func (w *World) Spawn() Entity {
+	e := w.next
+	w.next++
+	return e
+}
+
+// Despawn removes an entity and all of its components
+func (w *World) Despawn(e Entity) {
+	w.Players.Remove(e)
+	w.Kinds.Remove(e)
+	w.Names.Remove(e)
+	w.Healths.Remove(e)
+	w.Regens.Remove(e)
+	w.Positions.Remove(e)
+	w.Inventories.Remove(e)
+	w.Stats.Remove(e)
+	w.AIs.Remove(e)
+	w.Effects.Remove(e)
+}
+
+// Player returns the entity the player controls
+func (w *World) Player() (Entity, bool) {
+	players := w.Players.Entities()
+	if len(players) == 0 {
+		return 0, false
+	}
+	return players[0], true
+}
+
+// System updates the world for one turn
+type System func(w *World)
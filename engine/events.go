@@ -0,0 +1,86 @@
+package engine
+
+// Event is something that happened in the game. The engine publishes events as it applies
+// the rules, and frontends subscribe to react to them without the rules knowing who listens.
+type Event interface {
+	event()
+}
+
+// DamageTaken is published when the player loses health
+type DamageTaken struct {
+	Amount float64
+	Health float64 // Health left afterwards
+	Max    float64
+}
+
+// Healed is published when the player recovers health by an action
+type Healed struct {
+	Amount float64
+	Health float64
+	Max    float64
+}
+
+// PlayerDied is published once, when the player's health runs out
+type PlayerDied struct{}
+
+// ItemPickedUp is published when the player takes an item
+type ItemPickedUp struct {
+	Item string
+}
+
+// LevelEntered is published when the player arrives on a dungeon level
+type LevelEntered struct {
+	Depth int
+}
+
+// Message is text a script wants the player to read
+type Message struct {
+	Text string
+}
+
+// ScriptFailed is published when a script errors or runs out of steps
+type ScriptFailed struct {
+	Script string
+	Err    error
+}
+
+func (DamageTaken) event()  {}
+func (Healed) event()       {}
+func (PlayerDied) event()   {}
+func (ItemPickedUp) event() {}
+func (LevelEntered) event() {}
+func (Message) event()      {}
+func (ScriptFailed) event() {}
+
+// Handler reacts to a published event
+type Handler func(Event)
+
+// Bus delivers events to subscribers in the order they subscribed
+type Bus struct {
+	handlers map[int]Handler
+	order    []int
+	nextID   int
+}
+
+// Subscribe registers a handler and returns a function that removes it
+func (b *Bus) Subscribe(handler Handler) (unsubscribe func()) {
+	if b.handlers == nil {
+		b.handlers = map[int]Handler{}
+	}
+	id := b.nextID
+	b.nextID++
+	b.handlers[id] = handler
+	b.order = append(b.order, id)
+	return func() {
+		delete(b.handlers, id)
+	}
+}
+
+// Publish hands an event to every subscriber
+func (b *Bus) Publish(e Event) {
+	for _, id := range b.order {
+		if handler, ok := b.handlers[id]; ok {
+			handler(e)
+		}
+	}
+}
@@ -0,0 +1,370 @@
+// Package engine holds the game rules and state with no terminal or UI dependencies.
+// Frontends drive a Game by applying actions and advancing turns, then query it to draw.
+package engine
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"maps"
+	"math"
+	"math/rand/v2"
+	"slices"
+
+	"go.starlark.net/starlark"
+)
+
+const (
+	MaxHealth   = 100.0 // Health of players in saves from before classes
+	MinHealth   = 0
+	HealthRegen = 0.05 // Health recovered each turn while wounded
+	hitDamage   = 10
+	restHealing = 10
+)
+
+var (
+	ErrUnknownAction = errors.New("unknown action")
+	ErrGameOver      = errors.New("the player is dead")
+	ErrNotCarried    = errors.New("not carrying that")
+	ErrCantUse       = errors.New("can't be used")
+	ErrUnknownID     = errors.New("not in the content")
+	ErrOffMap        = errors.New("outside the dungeon")
+	ErrCheated       = errors.New("the run was changed by developer commands")
+	errNoPlayer      = errors.New("no player entity")
+)
+
+// Action is something the player does on their turn
+type Action string
+
+const (
+	ActionHit  Action = "hit"  // Take a blow, used to exercise combat until monsters exist
+	ActionRest Action = "rest" // Recover some health
+	ActionUse  Action = "use"  // Use an item, recorded by Use rather than applied
+)
+
+// State is everything needed to save and restore a game
+type State struct {
+	Entities []EntityState // Every actor and item with its components
+	Seed     uint64
+	RNG      map[Stream][]byte // Position of each random stream used so far
+	Turn     int               // Turns advanced since the run began
+	Steps    []Step            // Every action taken, so the run can be replayed
+	Cheated  bool              // Changed outside the rules, so the steps no longer reproduce it
+}
+
+// Outcome reports what an action or turn changed so frontends can react to it
+type Outcome struct {
+	HealthDelta float64
+	Died        bool
+}
+
+// Game is a single run. It is not safe for concurrent use.
+type Game struct {
+	world  *World
+	player Entity
+	state  State // Seed, turn and recording, the entities live in world
+	rng    *RNG
+	events Bus
+
+	content      *Content                     // Definitions items and scripts refer to, see SetContent
+	programs     map[string]*starlark.Program // Compiled scripts by ID
+	scripting    bool                         // A script is running
+	invulnerable bool                         // The player ignores damage, for testing, never saved
+}
+
+// New starts a fresh run as the given class. Runs with the same seed and class play out the same.
+func New(seed uint64, class ClassDef) *Game {
+	g := &Game{world: NewWorld(), rng: NewRNG(seed), state: State{Seed: seed}}
+	g.player = g.world.Spawn()
+	g.world.Players.Set(g.player, Player{})
+	g.world.Kinds.Set(g.player, Kind(class.ID))
+	g.world.Names.Set(g.player, "you")
+	g.world.Healths.Set(g.player, Health{Current: class.Health, Max: class.Health})
+	g.world.Regens.Set(g.player, Regen{PerTurn: HealthRegen})
+	g.world.Inventories.Set(g.player, Inventory{Items: slices.Clone(class.Items)})
+	g.world.Stats.Set(g.player, Stats{Values: maps.Clone(class.Stats)})
+	return g
+}
+
+// Restore resumes a run from a saved state
+func Restore(state State) (*Game, error) {
+	rng, err := restoreRNG(state.Seed, state.RNG)
+	if err != nil {
+		return nil, err
+	}
+	world, err := loadWorld(state.Entities)
+	if err != nil {
+		return nil, err
+	}
+	player, ok := world.Player()
+	if !ok {
+		return nil, errNoPlayer
+	}
+	g := &Game{world: world, player: player, state: state.clone(), rng: rng}
+	g.state.Entities = nil // The world is the source of truth from here on
+	g.state.RNG = nil      // As are the live streams
+	return g, nil
+}
+
+// State returns a copy of the current state, safe to save or inspect
+func (g *Game) State() State {
+	state := g.state.clone()
+	state.Entities = saveWorld(g.world)
+	state.RNG = g.rng.snapshot()
+	return state
+}
+
+func (s State) clone() State {
+	s.Entities = slices.Clone(s.Entities)
+	s.RNG = maps.Clone(s.RNG)
+	s.Steps = slices.Clone(s.Steps)
+	return s
+}
+
+// Validate returns every way the state breaks the game rules
+func (s State) Validate() []error {
+	var problems []error
+	players := 0
+	for _, entity := range s.Entities {
+		if entity.Player {
+			players++
+			if entity.Stats == nil || entity.Stats.Values == nil {
+				problems = append(problems, errors.New("missing stats"))
+			}
+		}
+		if health := entity.Health; health != nil && (health.Current < MinHealth || health.Current > health.Max) {
+			problems = append(problems, fmt.Errorf("health %.1f outside %d-%.0f", health.Current, MinHealth, health.Max))
+		}
+	}
+	if players != 1 {
+		problems = append(problems, fmt.Errorf("%d player entities, want 1", players))
+	}
+	if _, err := loadWorld(s.Entities); err != nil {
+		problems = append(problems, err)
+	}
+	if _, err := restoreRNG(s.Seed, s.RNG); err != nil {
+		problems = append(problems, err)
+	}
+	if n := len(s.Steps); n > 0 && s.Steps[n-1].Turn > s.Turn {
+		problems = append(problems, fmt.Errorf("action recorded on turn %d after the last turn %d", s.Steps[n-1].Turn, s.Turn))
+	}
+	return problems
+}
+
+// World gives frontends and tools direct access to the entities of the run
+func (g *Game) World() *World { return g.world }
+
+// Player is the entity the player controls
+func (g *Game) Player() Entity { return g.player }
+
+func (g *Game) Health() float64 { return g.playerHealth().Current }
+
+func (g *Game) MaxHealth() float64 { return g.playerHealth().Max }
+
+// Class is the content ID of the player's class
+func (g *Game) Class() string {
+	if kind, ok := g.world.Kinds.Get(g.player); ok {
+		return string(*kind)
+	}
+	return DefaultClass
+}
+
+// HealthFraction is health as a fraction of the maximum, for drawing bars
+func (g *Game) HealthFraction() float64 {
+	health := g.playerHealth()
+	if health.Max <= 0 {
+		return 0
+	}
+	return health.Current / health.Max
+}
+
+func (g *Game) playerHealth() Health {
+	if health, ok := g.world.Healths.Get(g.player); ok {
+		return *health
+	}
+	return Health{}
+}
+
+func (g *Game) Inventory() []string {
+	if inventory, ok := g.world.Inventories.Get(g.player); ok {
+		return slices.Clone(inventory.Items)
+	}
+	return nil
+}
+
+func (g *Game) Stats() map[string]int {
+	if stats, ok := g.world.Stats.Get(g.player); ok {
+		return maps.Clone(stats.Values)
+	}
+	return nil
+}
+
+func (g *Game) Seed() uint64 { return g.state.Seed }
+
+// Subscribe registers a handler for the events of this run
+func (g *Game) Subscribe(handler Handler) (unsubscribe func()) {
+	return g.events.Subscribe(handler)
+}
+
+// Rand returns the random stream a subsystem should draw from
+func (g *Game) Rand(stream Stream) *rand.Rand { return g.rng.Stream(stream) }
+
+func (g *Game) Turn() int { return g.state.Turn }
+
+// Recording returns the actions taken so far, for replaying the run
+func (g *Game) Recording() Replay {
+	return Replay{Seed: g.state.Seed, Class: g.Class(), Turns: g.state.Turn, Steps: slices.Clone(g.state.Steps), Cheated: g.state.Cheated}
+}
+
+// MarkCheated records that the run was changed outside the rules, such as from a
+// developer console, so its recording can't be trusted to replay it
+func (g *Game) MarkCheated() { g.state.Cheated = true }
+
+// SetInvulnerable makes the player ignore damage, so content can be tested without dying
+func (g *Game) SetInvulnerable(on bool) { g.invulnerable = on }
+
+func (g *Game) Invulnerable() bool { return g.invulnerable }
+
+// Dead reports whether the run is over
+func (g *Game) Dead() bool { return g.Health() <= MinHealth }
+
+// Apply performs a player action
+func (g *Game) Apply(action Action) (Outcome, error) {
+	if g.Dead() {
+		return Outcome{}, ErrGameOver
+	}
+	var outcome Outcome
+	switch action {
+	case ActionHit:
+		outcome = g.changeHealth(-hitDamage)
+		g.publish(DamageTaken{Amount: -outcome.HealthDelta, Health: g.Health(), Max: g.MaxHealth()})
+	case ActionRest:
+		outcome = g.changeHealth(restHealing)
+		g.publish(Healed{Amount: outcome.HealthDelta, Health: g.Health(), Max: g.MaxHealth()})
+	default:
+		return Outcome{}, fmt.Errorf("%w %q", ErrUnknownAction, action)
+	}
+	g.state.Steps = append(g.state.Steps, Step{Turn: g.state.Turn, Action: action})
+	if outcome.Died {
+		g.publish(PlayerDied{})
+	}
+	return outcome, nil
+}
+
+// Advance runs one turn of the world, running every system in order
+func (g *Game) Advance() Outcome {
+	if g.Dead() {
+		return Outcome{Died: true}
+	}
+	g.state.Turn++
+	before := g.Health()
+	for _, system := range systems {
+		system(g.world)
+	}
+	return Outcome{HealthDelta: g.Health() - before, Died: g.Dead()}
+}
+
+func (g *Game) changeHealth(delta float64) Outcome {
+	health, ok := g.world.Healths.Get(g.player)
+	if !ok {
+		return Outcome{}
+	}
+	if delta < 0 && g.invulnerable {
+		delta = 0
+	}
+	before := health.Current
+	health.Current = math.Min(health.Max, math.Max(MinHealth, before+delta))
+	return Outcome{HealthDelta: health.Current - before, Died: g.Dead()}
+}
+
+// Use uses an item from the player's pack: potions heal, and items with a script run it.
+// Potions and scrolls are used up.
+func (g *Game) Use(item string) (Outcome, error) {
+	if g.Dead() {
+		return Outcome{}, ErrGameOver
+	}
+	inventory, ok := g.world.Inventories.Get(g.player)
+	if !ok || !slices.Contains(inventory.Items, item) {
+		return Outcome{}, fmt.Errorf("%w: %s", ErrNotCarried, item)
+	}
+	def, ok := g.itemDef(item)
+	if !ok || (def.Heal == 0 && def.Script == "") {
+		return Outcome{}, fmt.Errorf("%s %w", cmp.Or(def.Name, item), ErrCantUse)
+	}
+
+	before := g.Health()
+	if def.Kind == "potion" || def.Kind == "scroll" {
+		i := slices.Index(inventory.Items, item)
+		inventory.Items = slices.Delete(inventory.Items, i, i+1)
+	}
+	if def.Heal > 0 {
+		outcome := g.changeHealth(def.Heal)
+		g.publish(Healed{Amount: outcome.HealthDelta, Health: g.Health(), Max: g.MaxHealth()})
+	}
+	if def.Script != "" {
+		g.runScript(def.Script)
+	}
+	g.state.Steps = append(g.state.Steps, Step{Turn: g.state.Turn, Action: ActionUse, Item: item})
+	return Outcome{HealthDelta: g.Health() - before, Died: g.Dead()}, nil
+}
+
+func (g *Game) itemDef(id string) (ItemDef, bool) {
+	if g.content == nil {
+		return ItemDef{}, false
+	}
+	def, ok := g.content.Items[id]
+	return def, ok
+}
+
+// Give puts an item in the player's pack
+func (g *Game) Give(item string) error {
+	def, ok := g.itemDef(item)
+	if !ok {
+		return fmt.Errorf("item %q %w", item, ErrUnknownID)
+	}
+	inventory, ok := g.world.Inventories.Get(g.player)
+	if !ok {
+		g.world.Inventories.Set(g.player, Inventory{})
+		inventory, _ = g.world.Inventories.Get(g.player)
+	}
+	inventory.Items = append(inventory.Items, item)
+	g.publish(ItemPickedUp{Item: def.Name})
+	return nil
+}
+
+// SpawnMonster brings a monster into the world where the player stands
+func (g *Game) SpawnMonster(id string) (Entity, error) {
+	if g.content == nil {
+		return 0, fmt.Errorf("monster %q %w", id, ErrUnknownID)
+	}
+	def, ok := g.content.Monsters[id]
+	if !ok {
+		return 0, fmt.Errorf("monster %q %w", id, ErrUnknownID)
+	}
+	monster := g.world.Spawn()
+	g.world.Kinds.Set(monster, Kind(def.ID))
+	g.world.Names.Set(monster, Name(def.Name))
+	g.world.Healths.Set(monster, Health{Current: def.Health, Max: def.Health})
+	g.world.AIs.Set(monster, AI{Behavior: def.Behavior})
+	if position, ok := g.world.Positions.Get(g.player); ok {
+		g.world.Positions.Set(monster, *position)
+	}
+	return monster, nil
+}
+
+// Teleport moves the player, publishing LevelEntered when the depth changes
+func (g *Game) Teleport(to Position) error {
+	if g.content != nil {
+		d := g.content.Dungeon
+		if to.Depth < 1 || to.Depth > d.Depth || to.X < 0 || to.X >= d.Width || to.Y < 0 || to.Y >= d.Height {
+			return fmt.Errorf("depth %d at %d,%d is %w", to.Depth, to.X, to.Y, ErrOffMap)
+		}
+	}
+	from, placed := g.world.Positions.Get(g.player)
+	changedLevel := !placed || from.Depth != to.Depth
+	g.world.Positions.Set(g.player, to)
+	if changedLevel {
+		g.publish(LevelEntered{Depth: to.Depth})
+	}
+	return nil
+}
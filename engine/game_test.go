@@ -0,0 +1,196 @@
+package engine
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"slices"
+	"testing"
+)
+
+// testContent loads the built-in content, failing the test if it doesn't validate
+func testContent(t testing.TB) *Content {
+	t.Helper()
+	content, err := DefaultContent()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return content
+}
+
+// near compares health, which picks up rounding from fractional regeneration
+func near(a, b float64) bool { return math.Abs(a-b) < 1e-9 }
+
+func newTestGame(t testing.TB, seed uint64) *Game {
+	t.Helper()
+	return New(seed, testContent(t).Classes[DefaultClass])
+}
+
+func TestNewGame(t *testing.T) {
+	class := testContent(t).Classes[DefaultClass]
+	g := New(7, class)
+	if g.Health() != class.Health || g.MaxHealth() != class.Health {
+		t.Errorf("health %.1f/%.1f, want %.1f/%.1f", g.Health(), g.MaxHealth(), class.Health, class.Health)
+	}
+	if got := g.Inventory(); !slices.Equal(got, class.Items) {
+		t.Errorf("inventory %v, want %v", got, class.Items)
+	}
+	if got := g.Stats(); !reflect.DeepEqual(got, class.Stats) {
+		t.Errorf("stats %v, want %v", got, class.Stats)
+	}
+	if g.Class() != class.ID || g.Seed() != 7 || g.Turn() != 0 || g.Dead() {
+		t.Errorf("new game is class %s, seed %d, turn %d, dead %v", g.Class(), g.Seed(), g.Turn(), g.Dead())
+	}
+}
+
+func TestApply(t *testing.T) {
+	g := newTestGame(t, 1)
+	outcome, err := g.Apply(ActionHit)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if outcome.HealthDelta != -hitDamage || g.Health() != g.MaxHealth()-hitDamage {
+		t.Errorf("hit changed health by %.1f to %.1f", outcome.HealthDelta, g.Health())
+	}
+	g.Advance()
+	outcome, err = g.Apply(ActionRest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g.Health() != g.MaxHealth() {
+		t.Errorf("resting left health at %.1f, want %.1f", g.Health(), g.MaxHealth())
+	}
+	if want := hitDamage - HealthRegen; !near(outcome.HealthDelta, want) {
+		t.Errorf("rest healed %.2f, want %.2f capped at the maximum", outcome.HealthDelta, want)
+	}
+	if _, err := g.Apply("dance"); !errors.Is(err, ErrUnknownAction) {
+		t.Errorf("unknown action returned %v, want ErrUnknownAction", err)
+	}
+
+	want := []Step{{Turn: 0, Action: ActionHit}, {Turn: 1, Action: ActionRest}}
+	if got := g.State().Steps; !slices.Equal(got, want) {
+		t.Errorf("recorded %v, want %v", got, want)
+	}
+}
+
+func TestAdvanceRegenerates(t *testing.T) {
+	g := newTestGame(t, 1)
+	g.Apply(ActionHit)
+	before := g.Health()
+	outcome := g.Advance()
+	if g.Turn() != 1 {
+		t.Errorf("turn %d after advancing, want 1", g.Turn())
+	}
+	if !near(outcome.HealthDelta, HealthRegen) || !near(g.Health(), before+HealthRegen) {
+		t.Errorf("advancing changed health by %.2f, want %.2f", outcome.HealthDelta, HealthRegen)
+	}
+}
+
+func TestDeath(t *testing.T) {
+	g := newTestGame(t, 1)
+	var died []Event
+	g.Subscribe(func(e Event) {
+		if _, ok := e.(PlayerDied); ok {
+			died = append(died, e)
+		}
+	})
+
+	var outcome Outcome
+	for hits := 0; !outcome.Died; hits++ {
+		if hits > 100 {
+			t.Fatal("the player never died")
+		}
+		var err error
+		if outcome, err = g.Apply(ActionHit); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if !g.Dead() || g.Health() != MinHealth {
+		t.Errorf("after dying health is %.1f and dead is %v", g.Health(), g.Dead())
+	}
+	if len(died) != 1 {
+		t.Errorf("PlayerDied published %d times, want 1", len(died))
+	}
+
+	steps := len(g.State().Steps)
+	if _, err := g.Apply(ActionRest); !errors.Is(err, ErrGameOver) {
+		t.Errorf("acting after death returned %v, want ErrGameOver", err)
+	}
+	if _, err := g.Use("potion"); !errors.Is(err, ErrGameOver) {
+		t.Errorf("using an item after death returned %v, want ErrGameOver", err)
+	}
+	if outcome := g.Advance(); !outcome.Died || g.Turn() != 0 {
+		t.Errorf("advancing after death gave %+v on turn %d, want the run to stay over", outcome, g.Turn())
+	}
+	if got := len(g.State().Steps); got != steps {
+		t.Errorf("%d steps recorded after death, want %d", got-steps, 0)
+	}
+}
+
+func TestRestoreRoundTrip(t *testing.T) {
+	g := newTestGame(t, 99)
+	for turn := range 30 {
+		if turn%7 == 0 {
+			g.Apply(ActionHit)
+		}
+		g.Rand(StreamCombat).Uint64()
+		g.Advance()
+	}
+	state := g.State()
+
+	restored, err := Restore(state)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := restored.State(); !reflect.DeepEqual(got, state) {
+		t.Fatalf("Restore(State()) changed the state:\n got %+v\nwant %+v", got, state)
+	}
+
+	// Both runs carry on the same way, random streams included
+	for _, game := range []*Game{g, restored} {
+		game.Apply(ActionHit)
+		game.Advance()
+	}
+	if got, want := restored.Rand(StreamCombat).Uint64(), g.Rand(StreamCombat).Uint64(); got != want {
+		t.Errorf("restored combat stream drew %d, want %d", got, want)
+	}
+	if got, want := restored.State(), g.State(); !reflect.DeepEqual(got, want) {
+		t.Errorf("restored run diverged:\n got %+v\nwant %+v", got, want)
+	}
+}
+
+func TestRestoreRejectsStateWithoutPlayer(t *testing.T) {
+	state := newTestGame(t, 1).State()
+	state.Entities = nil
+	if _, err := Restore(state); err == nil {
+		t.Error("restoring a state with no player succeeded, want an error")
+	}
+}
+
+func TestEventsPublishedWhereTheyHappen(t *testing.T) {
+	g := newTestGame(t, 1)
+	g.SetContent(testContent(t))
+	var events []Event
+	g.Subscribe(func(e Event) { events = append(events, e) })
+
+	g.Apply(ActionHit)
+	g.Apply(ActionRest)
+	if err := g.Give("sword"); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.Teleport(Position{Depth: 2, X: 1, Y: 1}); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.Teleport(Position{Depth: 2, X: 5, Y: 1}); err != nil { // Same level, nothing to announce
+		t.Fatal(err)
+	}
+	want := []Event{
+		DamageTaken{Amount: hitDamage, Health: 90, Max: 100},
+		Healed{Amount: restHealing, Health: 100, Max: 100},
+		ItemPickedUp{Item: "Sword"},
+		LevelEntered{Depth: 2},
+	}
+	if !reflect.DeepEqual(events, want) {
+		t.Errorf("published %+v, want %+v", events, want)
+	}
+}
@@ -0,0 +1,90 @@
+package engine
+
+import "fmt"
+
+// Step is a player action and the turn it was taken on
+type Step struct {
+	Turn   int    `json:"turn"`
+	Action Action `json:"action"`
+	Item   string `json:"item,omitempty"` // Item used, for ActionUse
+}
+
+// Replay is everything needed to play a run back. Since a run is decided by its seed
+// and the player's actions, replaying them against a fresh game reproduces it exactly.
+type Replay struct {
+	Seed    uint64
+	Class   string // Content ID of the class the run started as
+	Turns   int
+	Steps   []Step
+	Cheated bool // The run was changed in ways the steps don't record
+}
+
+// Empty reports whether there is nothing to play back
+func (r Replay) Empty() bool {
+	return r.Turns == 0 && len(r.Steps) == 0
+}
+
+// Replayer plays a recorded run back one turn at a time
+type Replayer struct {
+	replay  Replay
+	class   ClassDef
+	content *Content
+	game    *Game
+	next    int // Index of the next step to apply
+}
+
+// NewReplayer prepares a replay, failing if the run was cheated or the content no longer
+// has the run's class
+func NewReplayer(replay Replay, content *Content) (*Replayer, error) {
+	if replay.Cheated {
+		return nil, ErrCheated
+	}
+	class, ok := content.Classes[replay.Class]
+	if !ok {
+		return nil, fmt.Errorf("unknown class %q", replay.Class)
+	}
+	p := &Replayer{replay: replay, class: class, content: content}
+	p.Seek(0)
+	return p, nil
+}
+
+// Game is the run as it stood at the current turn
+func (p *Replayer) Game() *Game { return p.game }
+
+func (p *Replayer) Turn() int { return p.game.state.Turn }
+
+func (p *Replayer) Turns() int { return p.replay.Turns }
+
+// Done reports whether every recorded turn and action has been played
+func (p *Replayer) Done() bool {
+	return p.next >= len(p.replay.Steps) && (p.Turn() >= p.replay.Turns || p.game.Dead())
+}
+
+// Step applies the actions taken on the current turn, then advances to the next one
+func (p *Replayer) Step() {
+	for p.next < len(p.replay.Steps) && p.replay.Steps[p.next].Turn <= p.Turn() {
+		// Recorded actions were valid when taken
+		if step := p.replay.Steps[p.next]; step.Action == ActionUse {
+			p.game.Use(step.Item)
+		} else {
+			p.game.Apply(step.Action)
+		}
+		p.next++
+	}
+	if p.Turn() < p.replay.Turns {
+		p.game.Advance()
+	}
+}
+
+// Seek jumps to a turn by replaying from the start, which is cheap since turns are small
+func (p *Replayer) Seek(turn int) {
+	p.game = New(p.replay.Seed, p.class)
+	p.game.SetContent(p.content)
+	p.next = 0
+	for p.Turn() < turn && !p.Done() {
+		p.Step()
+	}
+	if p.Turn() >= p.replay.Turns {
+		p.Step() // Play the actions taken on the last turn
+	}
+}
@@ -0,0 +1,94 @@
+package engine
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// recordRun plays a run that hits, rests and drinks potions, returning its recording
+// and the state at the start of every turn, before that turn's actions
+func recordRun(t *testing.T, content *Content, turns int) (Replay, []State) {
+	t.Helper()
+	g := New(2024, content.Classes[DefaultClass])
+	g.SetContent(content)
+	states := []State{g.State()}
+	for turn := range turns {
+		switch turn % 6 {
+		case 0, 1:
+			g.Apply(ActionHit)
+		case 3:
+			g.Apply(ActionRest)
+		}
+		if turn == 4 {
+			if _, err := g.Use("potion"); err != nil {
+				t.Fatal(err)
+			}
+		}
+		g.Advance()
+		states = append(states, g.State())
+	}
+	return g.Recording(), states
+}
+
+func TestReplayMatchesRecordedRun(t *testing.T) {
+	content := testContent(t)
+	recording, states := recordRun(t, content, 40)
+
+	replayer, err := NewReplayer(recording, content)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := replayer.Game().State(); !reflect.DeepEqual(got, states[0]) {
+		t.Fatalf("turn 0:\n got %+v\nwant %+v", got, states[0])
+	}
+	for !replayer.Done() {
+		replayer.Step()
+		turn := replayer.Turn()
+		if got := replayer.Game().State(); !reflect.DeepEqual(got, states[turn]) {
+			t.Fatalf("turn %d:\n got %+v\nwant %+v", turn, got, states[turn])
+		}
+	}
+	if replayer.Turn() != recording.Turns {
+		t.Errorf("replay stopped on turn %d, want %d", replayer.Turn(), recording.Turns)
+	}
+}
+
+func TestReplaySeek(t *testing.T) {
+	content := testContent(t)
+	recording, states := recordRun(t, content, 40)
+	replayer, err := NewReplayer(recording, content)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Backwards as well as forwards, since seeking replays from the start
+	for _, turn := range []int{25, 3, 39, 0, 12} {
+		replayer.Seek(turn)
+		if got := replayer.Game().State(); !reflect.DeepEqual(got, states[turn]) {
+			t.Errorf("seeking to turn %d:\n got %+v\nwant %+v", turn, got, states[turn])
+		}
+	}
+}
+
+func TestReplayUnknownClass(t *testing.T) {
+	if _, err := NewReplayer(Replay{Class: "bard"}, testContent(t)); err == nil {
+		t.Error("replaying a class the content lacks succeeded, want an error")
+	}
+}
+
+func TestReplayRefusesCheatedRun(t *testing.T) {
+	content := testContent(t)
+	g := New(1, content.Classes[DefaultClass])
+	g.SetContent(content)
+	g.Give("sword")
+	g.MarkCheated()
+	g.Advance()
+
+	restored, err := Restore(g.State())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewReplayer(restored.Recording(), content); !errors.Is(err, ErrCheated) {
+		t.Errorf("replaying a cheated run returned %v, want ErrCheated", err)
+	}
+}
@@ -0,0 +1,85 @@
+package engine
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math/rand/v2"
+	"time"
+)
+
+// Stream names an independent sequence of random numbers. Each subsystem draws from its
+// own stream so adding a roll in one place doesn't shift the results of another.
+type Stream string
+
+const (
+	StreamMapGen Stream = "mapgen"
+	StreamCombat Stream = "combat"
+	StreamLoot   Stream = "loot"
+	StreamAI     Stream = "ai"
+)
+
+// RNG hands out the random streams of a run, all derived from a single seed
+type RNG struct {
+	seed    uint64
+	sources map[Stream]*rand.PCG
+	streams map[Stream]*rand.Rand
+}
+
+func NewRNG(seed uint64) *RNG {
+	return &RNG{seed: seed, sources: map[Stream]*rand.PCG{}, streams: map[Stream]*rand.Rand{}}
+}
+
+// RandomSeed picks a seed for a run that wasn't given one
+func RandomSeed() uint64 {
+	return rand.Uint64()
+}
+
+// DailySeed is the seed everyone shares for the daily challenge on the given day
+func DailySeed(day time.Time) uint64 {
+	y, m, d := day.Date()
+	return uint64(y*10000 + int(m)*100 + d)
+}
+
+func (r *RNG) Seed() uint64 { return r.seed }
+
+// Stream returns the generator for a stream, starting it from the seed on first use
+func (r *RNG) Stream(name Stream) *rand.Rand {
+	if stream, ok := r.streams[name]; ok {
+		return stream
+	}
+	source := rand.NewPCG(r.seed, streamKey(name))
+	r.sources[name] = source
+	r.streams[name] = rand.New(source)
+	return r.streams[name]
+}
+
+// streamKey separates the streams of one seed from each other
+func streamKey(name Stream) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(name))
+	return h.Sum64()
+}
+
+// snapshot captures the position of every stream used so far
+func (r *RNG) snapshot() map[Stream][]byte {
+	if len(r.sources) == 0 {
+		return nil
+	}
+	states := make(map[Stream][]byte, len(r.sources))
+	for name, source := range r.sources {
+		states[name], _ = source.MarshalBinary() // PCG never fails to marshal
+	}
+	return states
+}
+
+// restoreRNG resumes streams from a snapshot, so a loaded run rolls what it would have
+func restoreRNG(seed uint64, states map[Stream][]byte) (*RNG, error) {
+	r := NewRNG(seed)
+	for name, state := range states {
+		r.Stream(name)
+		if err := r.sources[name].UnmarshalBinary(state); err != nil {
+			return nil, fmt.Errorf("random stream %q: %w", name, err)
+		}
+	}
+	return r, nil
+}
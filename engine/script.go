@@ -0,0 +1,218 @@
+package engine
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+
+	"go.starlark.net/starlark"
+	"go.starlark.net/syntax"
+)
+
+// maxScriptSteps bounds how much work one script run may do, so a runaway loop
+// fails the script instead of hanging the game
+const maxScriptSteps = 100_000
+
+// scriptTriggers name what can start a script: an event, or using the item that names it
+var scriptTriggers = []string{"use", "damage_taken", "healed", "player_died", "item_picked_up", "level_entered"}
+
+// scriptOptions allow the statements bespoke effects need, such as top-level if and while,
+// which the step limit keeps safe
+var scriptOptions = &syntax.FileOptions{TopLevelControl: true, While: true, GlobalReassign: true, Set: true}
+
+// scriptAPI are the names scripts can use besides Starlark's own built-ins
+var scriptAPI = []string{"damage", "heal", "spawn", "message", "teleport", "give", "health", "max_health", "turn", "depth"}
+
+// ScriptDef is a Starlark script for effects too bespoke for plain data, such as cursed
+// items or traps. Scripts can't read files or load modules, and only change the game
+// through the functions in scriptAPI.
+type ScriptDef struct {
+	ID   string `json:"id"`
+	On   string `json:"on"` // Trigger from scriptTriggers
+	Code string `json:"code"`
+
+	source string
+}
+
+// compile checks a script's syntax and that it only uses names it has
+func (s ScriptDef) compile() (*starlark.Program, error) {
+	isPredeclared := func(name string) bool { return slices.Contains(scriptAPI, name) }
+	_, program, err := starlark.SourceProgramOptions(scriptOptions, s.ID, s.Code, isPredeclared)
+	return program, err
+}
+
+// triggerOf names the trigger for an event, empty for events scripts can't hook
+func triggerOf(e Event) string {
+	switch e.(type) {
+	case DamageTaken:
+		return "damage_taken"
+	case Healed:
+		return "healed"
+	case PlayerDied:
+		return "player_died"
+	case ItemPickedUp:
+		return "item_picked_up"
+	case LevelEntered:
+		return "level_entered"
+	default:
+		return ""
+	}
+}
+
+// SetContent gives the run the definitions its items and scripts refer to, including
+// the scripts that events trigger. Replays must use the same content to match.
+func (g *Game) SetContent(content *Content) {
+	g.content = content
+	g.programs = map[string]*starlark.Program{}
+	for id, script := range content.Scripts {
+		if program, err := script.compile(); err == nil { // Validate has reported the rest
+			g.programs[id] = program
+		}
+	}
+}
+
+// publish tells subscribers about an event, then runs the scripts it triggers, so
+// whatever a script does follows the event for everyone listening
+func (g *Game) publish(e Event) {
+	g.events.Publish(e)
+	trigger := triggerOf(e)
+	if trigger == "" || g.scripting || g.content == nil {
+		return // Scripts don't set each other off, so they can't loop through events
+	}
+	for _, id := range slices.Sorted(maps.Keys(g.content.Scripts)) {
+		if g.content.Scripts[id].On == trigger {
+			g.runScript(id)
+		}
+	}
+}
+
+// runScript runs a script against the game, publishing ScriptFailed if it goes wrong.
+// Whatever the script changed before failing stays changed.
+func (g *Game) runScript(id string) {
+	program, ok := g.programs[id]
+	if !ok {
+		g.publish(ScriptFailed{Script: id, Err: errors.New("not defined or doesn't compile")})
+		return
+	}
+	thread := &starlark.Thread{
+		Name: id,
+		// Starlark prints to stderr by default, which would draw over the screen
+		Print: func(_ *starlark.Thread, text string) { g.publish(Message{Text: text}) },
+	}
+	thread.SetMaxExecutionSteps(maxScriptSteps)
+	g.scripting = true
+	defer func() { g.scripting = false }()
+	if _, err := program.Init(thread, g.scriptGlobals()); err != nil {
+		g.publish(ScriptFailed{Script: id, Err: err})
+	}
+}
+
+// scriptGlobals builds the API a script sees, snapshotting the values it reads
+func (g *Game) scriptGlobals() starlark.StringDict {
+	depth := 0
+	if position, ok := g.world.Positions.Get(g.player); ok {
+		depth = position.Depth
+	}
+	return starlark.StringDict{
+		"damage":     starlark.NewBuiltin("damage", g.scriptDamage),
+		"heal":       starlark.NewBuiltin("heal", g.scriptHeal),
+		"spawn":      starlark.NewBuiltin("spawn", g.scriptSpawn),
+		"message":    starlark.NewBuiltin("message", g.scriptMessage),
+		"teleport":   starlark.NewBuiltin("teleport", g.scriptTeleport),
+		"give":       starlark.NewBuiltin("give", g.scriptGive),
+		"health":     starlark.Float(g.Health()),
+		"max_health": starlark.Float(g.MaxHealth()),
+		"turn":       starlark.MakeInt(g.state.Turn),
+		"depth":      starlark.MakeInt(depth),
+	}
+}
+
+// amount unpacks a non-negative number, so scripts can write damage(5) as well as damage(2.5)
+type amount float64
+
+func (a *amount) Unpack(v starlark.Value) error {
+	f, ok := starlark.AsFloat(v)
+	if !ok {
+		return fmt.Errorf("got %s, want number", v.Type())
+	}
+	if f < 0 {
+		return errors.New("can't be negative")
+	}
+	*a = amount(f)
+	return nil
+}
+
+// damage(amount) hurts the player
+func (g *Game) scriptDamage(_ *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var hurt amount
+	if err := starlark.UnpackArgs(fn.Name(), args, kwargs, "amount", &hurt); err != nil {
+		return nil, err
+	}
+	outcome := g.changeHealth(-float64(hurt))
+	g.publish(DamageTaken{Amount: -outcome.HealthDelta, Health: g.Health(), Max: g.MaxHealth()})
+	if outcome.Died {
+		g.publish(PlayerDied{})
+	}
+	return starlark.None, nil
+}
+
+// heal(amount) restores the player's health
+func (g *Game) scriptHeal(_ *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var healing amount
+	if err := starlark.UnpackArgs(fn.Name(), args, kwargs, "amount", &healing); err != nil {
+		return nil, err
+	}
+	outcome := g.changeHealth(float64(healing))
+	g.publish(Healed{Amount: outcome.HealthDelta, Health: g.Health(), Max: g.MaxHealth()})
+	return starlark.None, nil
+}
+
+// spawn(monster) brings a monster into being where the player stands
+func (g *Game) scriptSpawn(_ *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var id string
+	if err := starlark.UnpackArgs(fn.Name(), args, kwargs, "monster", &id); err != nil {
+		return nil, err
+	}
+	if _, err := g.SpawnMonster(id); err != nil {
+		return nil, fmt.Errorf("%s: %w", fn.Name(), err)
+	}
+	return starlark.None, nil
+}
+
+// message(text) tells the player something
+func (g *Game) scriptMessage(_ *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var text string
+	if err := starlark.UnpackArgs(fn.Name(), args, kwargs, "text", &text); err != nil {
+		return nil, err
+	}
+	g.publish(Message{Text: text})
+	return starlark.None, nil
+}
+
+// teleport(x, y, depth=current) moves the player
+func (g *Game) scriptTeleport(_ *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	position := Position{Depth: 1}
+	if current, ok := g.world.Positions.Get(g.player); ok {
+		position = *current
+	}
+	if err := starlark.UnpackArgs(fn.Name(), args, kwargs, "x", &position.X, "y", &position.Y, "depth?", &position.Depth); err != nil {
+		return nil, err
+	}
+	if err := g.Teleport(position); err != nil {
+		return nil, fmt.Errorf("%s: %w", fn.Name(), err)
+	}
+	return starlark.None, nil
+}
+
+// give(item) puts an item in the player's pack
+func (g *Game) scriptGive(_ *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var id string
+	if err := starlark.UnpackArgs(fn.Name(), args, kwargs, "item", &id); err != nil {
+		return nil, err
+	}
+	if err := g.Give(id); err != nil {
+		return nil, fmt.Errorf("%s: %w", fn.Name(), err)
+	}
+	return starlark.None, nil
+}
@@ -0,0 +1,109 @@
+package engine
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// scriptedGame starts a run whose content adds a usable item that runs the given code
+func scriptedGame(t *testing.T, code string) *Game {
+	t.Helper()
+	content := testContent(t).Clone()
+	content.Scripts["test"] = ScriptDef{ID: "test", On: "use", Code: code}
+	content.Items["test_idol"] = ItemDef{ID: "test_idol", Name: "Test Idol", Kind: "trinket", Glyph: "&", Script: "test"}
+	if err := content.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	g := New(1, content.Classes[DefaultClass])
+	g.SetContent(content)
+	if err := g.Give("test_idol"); err != nil {
+		t.Fatal(err)
+	}
+	return g
+}
+
+func TestScriptStepLimit(t *testing.T) {
+	g := scriptedGame(t, "while True:\n    pass")
+	var failed []ScriptFailed
+	g.Subscribe(func(e Event) {
+		if e, ok := e.(ScriptFailed); ok {
+			failed = append(failed, e)
+		}
+	})
+	if _, err := g.Use("test_idol"); err != nil {
+		t.Fatal(err)
+	}
+	if len(failed) != 1 || failed[0].Script != "test" {
+		t.Fatalf("published %+v, want one ScriptFailed for the test script", failed)
+	}
+	if err := failed[0].Err; err == nil || !strings.Contains(err.Error(), "too many steps") {
+		t.Errorf("script failed with %v, want it stopped for running too many steps", err)
+	}
+}
+
+func TestScriptEffects(t *testing.T) {
+	g := scriptedGame(t, `damage(5)
+give("sword")
+message("health %d" % int(health))`)
+	var messages []string
+	g.Subscribe(func(e Event) {
+		switch e := e.(type) {
+		case Message:
+			messages = append(messages, e.Text)
+		case ScriptFailed:
+			t.Errorf("script failed: %v", e.Err)
+		}
+	})
+	before := len(g.Inventory())
+	outcome, err := g.Use("test_idol")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if outcome.HealthDelta != -5 {
+		t.Errorf("health changed by %.1f, want -5", outcome.HealthDelta)
+	}
+	if got := len(g.Inventory()); got != before+1 {
+		t.Errorf("carrying %d items, want %d with the idol kept and a sword given", got, before+1)
+	}
+	// Scripts see the values from when they started
+	if want := []string{"health 100"}; !reflect.DeepEqual(messages, want) {
+		t.Errorf("messages %q, want %q", messages, want)
+	}
+}
+
+// Triggered scripts run once everyone has heard of the event, whenever they subscribed,
+// so the message log shows the damage before the script's reaction to it
+func TestTriggeredScriptsFollowTheirEvent(t *testing.T) {
+	g := newTestGame(t, 1)
+	g.SetContent(testContent(t)) // Before subscribing, as frontends do
+	var events []string
+	g.Subscribe(func(e Event) {
+		events = append(events, reflect.TypeOf(e).Name())
+	})
+	for g.Health() > g.MaxHealth()/4 {
+		if _, err := g.Apply(ActionHit); err != nil {
+			t.Fatal(err)
+		}
+	}
+	n := len(events)
+	if n < 2 || events[n-2] != "DamageTaken" || events[n-1] != "Message" {
+		t.Errorf("events %v, want the near death message after the damage that caused it", events)
+	}
+}
+
+func TestScriptPrintIsAMessage(t *testing.T) {
+	g := scriptedGame(t, `print("the idol hums", 3)`)
+	var messages []string
+	g.Subscribe(func(e Event) {
+		if e, ok := e.(Message); ok {
+			messages = append(messages, e.Text)
+		}
+	})
+	if _, err := g.Use("test_idol"); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"the idol hums 3"}; !reflect.DeepEqual(messages, want) {
+		t.Errorf("messages %q, want %q", messages, want)
+	}
+}
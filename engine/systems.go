@@ -0,0 +1,28 @@
+package engine
+
+import "math"
+
+// systems run in order at the end of every turn
+var systems = []System{regenSystem, statusEffectSystem}
+
+// regenSystem recovers health for wounded entities that regenerate
+func regenSystem(w *World) {
+	Join(&w.Healths, &w.Regens, func(_ Entity, health *Health, regen *Regen) {
+		if health.Current > MinHealth && health.Current < health.Max {
+			health.Current = math.Min(health.Max, health.Current+regen.PerTurn)
+		}
+	})
+}
+
+// statusEffectSystem counts down status effects and drops the ones that have worn off
+func statusEffectSystem(w *World) {
+	w.Effects.Each(func(_ Entity, effects *StatusEffects) {
+		active := effects.Active[:0]
+		for _, effect := range effects.Active {
+			if effect.Turns--; effect.Turns > 0 {
+				active = append(active, effect)
+			}
+		}
+		effects.Active = active
+	})
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"errors"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// errorCategory groups errors by what went wrong so the error screen can explain it
+type errorCategory int
+
+const (
+	errorInternal errorCategory = iota
+	errorSaveIO
+	errorCorruptSave
+	errorContent
+)
+
+func (c errorCategory) String() string {
+	switch c {
+	case errorSaveIO:
+		return "Save I/O"
+	case errorCorruptSave:
+		return "Corrupt Save"
+	case errorContent:
+		return "Content"
+	default:
+		return "Internal Error"
+	}
+}
+
+// errorAction is a recovery option offered on the error screen
+type errorAction struct {
+	label   string
+	handler func(m *model) tea.Cmd
+}
+
+// gameError wraps an error with a player facing message and ways to recover from it
+type gameError struct {
+	category errorCategory
+	message  string
+	err      error
+	actions  []errorAction
+}
+
+func newGameError(category errorCategory, message string, err error, actions ...errorAction) *gameError {
+	return &gameError{
+		category: category,
+		message:  message,
+		err:      err,
+		actions:  actions,
+	}
+}
+
+func (e *gameError) Error() string {
+	if e.err == nil {
+		return e.message
+	}
+	return e.message + ": " + e.err.Error()
+}
+
+func (e *gameError) Unwrap() error {
+	return e.err
+}
+
+// asGameError returns err as a gameError, treating unexpected errors as internal
+func asGameError(err error) *gameError {
+	var gameErr *gameError
+	if errors.As(err, &gameErr) {
+		return gameErr
+	}
+	return newGameError(errorInternal, "Something went wrong", err)
+}
+
+// errorCmd returns a command that reports err to the model
+func errorCmd(err error) tea.Cmd {
+	return func() tea.Msg {
+		return err
+	}
+}
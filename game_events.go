@@ -0,0 +1,72 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/sss7526/dungeon_crawl/engine"
+)
+
+// gameEventMsg carries an engine event into the Bubble Tea loop, so screens react to
+// what happened in the game the same way they react to keys and ticks
+type gameEventMsg struct {
+	event engine.Event
+}
+
+// setGame replaces the current run, listening for its events
+func (m *model) setGame(game *engine.Game) {
+	m.game = game
+	m.pendingEvents = nil
+	game.SetContent(m.content)
+	game.Subscribe(func(e engine.Event) {
+		m.pendingEvents = append(m.pendingEvents, e)
+	})
+}
+
+// flushEvents turns the events published during an update into messages, in order
+func (m *model) flushEvents() tea.Cmd {
+	if len(m.pendingEvents) == 0 {
+		return nil
+	}
+	cmds := make([]tea.Cmd, len(m.pendingEvents))
+	for i, e := range m.pendingEvents {
+		cmds[i] = func() tea.Msg { return gameEventMsg{e} }
+	}
+	m.pendingEvents = nil
+	return tea.Sequence(cmds...)
+}
+
+// describeEvent words an event for the message log and the text mode, false if it isn't worth telling
+func describeEvent(e engine.Event) (severity, messageCategory, string, bool) {
+	switch e := e.(type) {
+	case engine.DamageTaken:
+		return severityWarning, categoryCombat, fmt.Sprintf("You take %.0f damage, health %.0f of %.0f", e.Amount, e.Health, e.Max), true
+	case engine.Healed:
+		return severityInfo, categoryCombat, fmt.Sprintf("You rest, health %.0f of %.0f", e.Health, e.Max), true
+	case engine.PlayerDied:
+		return severityError, categoryCombat, "You died", true
+	case engine.ItemPickedUp:
+		return severityInfo, categoryCombat, "You pick up the " + e.Item, true
+	case engine.LevelEntered:
+		return severityInfo, categorySystem, fmt.Sprintf("You enter depth %d", e.Depth), true
+	case engine.Message:
+		return severityInfo, categoryCombat, e.Text, true
+	case engine.ScriptFailed:
+		return severityWarning, categorySystem, fmt.Sprintf("Script %s failed: %v", e.Script, e.Err), true
+	default:
+		return severityInfo, "", "", false
+	}
+}
+
+// debugEvent writes an event to the debug log, including those the message log leaves out
+func debugEvent(e engine.Event) {
+	slog.Debug("game event", "type", fmt.Sprintf("%T", e), "event", e)
+}
+
+// logEvent records an event in the message log
+func (m *model) logEvent(e engine.Event) {
+	if sev, category, text, ok := describeEvent(e); ok {
+		m.log(sev, category, text)
+	}
+}
@@ -1,139 +1,382 @@
 package main
 
 import (
-	"encoding/json"
+	"cmp"
+	"errors"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
+	"unicode"
 	"fmt"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/sss7526/dungeon_crawl/engine"
 )
 
 const (
 	saveFilePrefix		= "save_"
 	saveFileExt			= ".json"
 	saveTimestampFmt	= "2006-01-02 15:04:05"
+	saveBackupExt		= ".bak"	// Previous contents of a save that was overwritten
+)
+
+// gameMode controls how death and saves interact for a run
+type gameMode string
+
+const (
+	modeCasual		gameMode = "casual"		// Saves stay loadable after death
+	modePermadeath	gameMode = "permadeath"	// Death deletes the run's save, loads consume it
 )
 
 type GameState struct {
-	Health 		float64				`json:"health"`
-	Inventory 	[]string 			`json:"inventory"`
+	Health 		float64				`json:"health"`		// Copies of the player's health, inventory and stats
+	Inventory 	[]string 			`json:"inventory"`	// for older versions, only read from saves without entities
 	Stats 		map[string]int 		`json:"stats"`
+	Entities	[]engine.EntityState	`json:"entities,omitempty"`	// Every entity with all of its components
+	Seed		uint64				`json:"seed,omitempty"`		// Seed the run was started from
+	RNG			map[engine.Stream][]byte	`json:"rng,omitempty"`	// Position of each random stream
+	Turn		int					`json:"turn,omitempty"`		// Turns played, the length of the replay
+	Steps		[]engine.Step		`json:"steps,omitempty"`	// Every action taken, for replays
+	Cheated		bool				`json:"cheated,omitempty"`	// Developer commands changed the run, so it can't be replayed
+	Mods		[]modRef			`json:"mods,omitempty"`		// Mods active when the run was saved
+	Name		string				`json:"name,omitempty"`		// The player's name, saves from before names use the default
+	Mode		gameMode			`json:"mode"`
+	Ranked		bool				`json:"ranked,omitempty"`		// Leaderboard or daily challenge run
 	Timestamp	time.Time			`json:"timestamp"`
+	Signature	string				`json:"signature,omitempty"`	// HMAC of the state, only set on ranked saves
+
+	fileName	string				// Name of the file the state was read from
 }
 
+// isPermadeath reports whether the save belongs to a permadeath run.
+// Saves written before modes existed have no mode and are treated as casual.
+func (g GameState) isPermadeath() bool {
+	return g.Mode == modePermadeath
+}
+
+// modeOrDefault returns the save's mode, treating saves from before modes existed as casual
+func (g GameState) modeOrDefault() gameMode {
+	if g.Mode == "" {
+		return modeCasual
+	}
+	return g.Mode
+}
+
+// rankStatus describes whether a save counts for the leaderboard, empty for casual saves
+func (g GameState) rankStatus() string {
+	switch {
+	case !g.Ranked:
+		return ""
+	case verifyGameState(g):
+		return "ranked"
+	default:
+		return "unranked (modified)"
+	}
+}
+
+// validateGameState checks a decoded save against the save schema and returns every problem found
+func validateGameState(g GameState) []error {
+	var problems []error
+	if g.Timestamp.IsZero() {
+		problems = append(problems, errors.New("missing timestamp"))
+	}
+	problems = append(problems, g.engineState().Validate()...)
+	switch g.Mode {
+	case "", modeCasual, modePermadeath:
+	default:
+		problems = append(problems, fmt.Errorf("unknown game mode %q", g.Mode))
+	}
+	return problems
+}
+
+// replay is the recording of the run kept in the save
+func (g GameState) replay() engine.Replay {
+	class := engine.DefaultClass
+	if player, ok := g.player(); ok && player.Kind != "" {
+		class = string(player.Kind)
+	}
+	return engine.Replay{Seed: g.Seed, Class: class, Turns: g.Turn, Steps: g.Steps, Cheated: g.Cheated}
+}
+
+// player returns the player's entity, if the save has entities
+func (g GameState) player() (engine.EntityState, bool) {
+	for _, entity := range g.Entities {
+		if entity.Player {
+			return entity, true
+		}
+	}
+	return engine.EntityState{}, false
+}
+
+// health is the player's health, from the top-level copy in saves from before entities
+func (g GameState) health() float64 {
+	if player, ok := g.player(); ok && player.Health != nil {
+		return player.Health.Current
+	}
+	return g.Health
+}
+
+// maxHealth is the player's maximum health, which saves from before classes don't record
+func (g GameState) maxHealth() float64 {
+	if player, ok := g.player(); ok && player.Health != nil {
+		return player.Health.Max
+	}
+	return engine.MaxHealth
+}
+
+// inventory is what the player carries, from the top-level copy in saves from before entities
+func (g GameState) inventory() []string {
+	if player, ok := g.player(); ok && player.Inventory != nil {
+		return engine.MigrateItems(player.Inventory.Items)
+	}
+	return engine.MigrateItems(g.Inventory)
+}
+
+// stats are the player's stats, from the top-level copy in saves from before entities
+func (g GameState) stats() map[string]int {
+	if player, ok := g.player(); ok && player.Stats != nil {
+		return player.Stats.Values
+	}
+	return g.Stats
+}
+
+// engineState is the part of a save the engine restores a run from. Saves from before
+// entities existed only have the player's fields, so the player is rebuilt from those.
+func (g GameState) engineState() engine.State {
+	entities := g.Entities
+	if len(entities) == 0 {
+		entities = []engine.EntityState{engine.PlayerState(g.Health, g.Inventory, g.Stats)}
+	}
+	return engine.State{Entities: entities, Seed: g.Seed, RNG: g.RNG, Turn: g.Turn, Steps: g.Steps, Cheated: g.Cheated}
+}
+
+// saveDirOverride replaces the save directory when set with --save-dir
+var saveDirOverride string
+
 // getSaveDir returns the directory for saving game files, creating if necessary
 func getSaveDir() (string, error) {
-	dir, err := os.UserConfigDir()
-	if err != nil {
-		return "", err
+	saveDir := saveDirOverride
+	if saveDir == "" {
+		dir, err := os.UserConfigDir()
+		if err != nil {
+			return "", err
+		}
+		saveDir = filepath.Join(dir, "dungeon_crawler") // Create a subdirectory for the game
 	}
-	saveDir := filepath.Join(dir, "dungeon_crawler") // Create a subdirectory for the game
 	if err := os.MkdirAll(saveDir, os.ModePerm); err != nil {
 		return "", err
 	}
 	return saveDir, nil
 }
 
-// listSavedGames returns a list of saved game files
-func listSavedGames() ([]GameState, error) {
-	saveDir, err := getSaveDir()
-	if err != nil {
-		return nil, err
+// backupName returns the name of the backup kept for a save
+func backupName(name string) string {
+	return name + saveBackupExt
+}
+
+// isBackupName reports whether a save name refers to a backup rather than a save
+func isBackupName(name string) bool {
+	return strings.HasSuffix(name, saveBackupExt)
+}
+
+// hasBackup reports whether a backup of the named save exists in the store
+func hasBackup(store SaveStore, name string) bool {
+	if isBackupName(name) {
+		return false
 	}
+	_, err := store.Read(backupName(name))
+	return err == nil
+}
 
-	entries, err := os.ReadDir(saveDir)
+// listSavedGames returns a list of saved games in the store
+func listSavedGames(store SaveStore) ([]GameState, error) {
+	names, err := store.Names()
 	if err != nil {
 		return nil, err
 	}
 
 	var saves []GameState
-	for _, entry := range entries {
-		if entry.IsDir() {
+	for _, name := range names {
+		if isBackupName(name) {
 			continue
 		}
-		file, err := os.Open(filepath.Join(saveDir, entry.Name()))
+		data, err := store.Read(name)
 		if err != nil {
 			continue
 		}
-		defer file.Close()
 
-		var gameState GameState
-		decoder := json.NewDecoder(file)
-		if err := decoder.Decode(&gameState); err != nil {
+		gameState, err := decodeGameState(data)
+		if err != nil {
 			continue
 		}
+		gameState.fileName = name
 		saves = append(saves, gameState)
 	}
 
 	return saves, nil
 }
 
-// saveGameState saves the current state of the game to a file.
+// saveGameState saves the current state of the game to the store.
 func (m *model) saveGameState() tea.Cmd {
-	saveDir, err := getSaveDir()
-	if err != nil {
-		return func() tea.Msg {
-			return err
-		}
+	// Permadeath runs keep a single save that is overwritten, casual runs get a new file per save
+	fileName := m.saveFile
+	if fileName == "" || m.mode != modePermadeath {
+		fileName = fmt.Sprintf("%s%d%s", saveFilePrefix, time.Now().Unix(), saveFileExtension(m.config.SaveFormat)) // Unique filename with timestamp
+	}
+	return m.saveGameStateAs(fileName)
+}
+
+// autosave overwrites the run's own save, so autosaves don't pile up as new files.
+// Runs that haven't been saved yet get a file the way a manual save would.
+func (m *model) autosave() tea.Cmd {
+	if m.saveFile == "" {
+		return m.saveGameState()
 	}
+	return m.saveGameStateAs(m.saveFile)
+}
 
-	fileName := fmt.Sprintf("%s%d%s", saveFilePrefix, time.Now().Unix(), saveFileExt) // Unique filename with timestamp
-	savePath := filepath.Join(saveDir, fileName)
+// slotFileName returns the save file name for a named slot, replacing characters that aren't safe in file names
+func slotFileName(slot, format string) string {
+	safe := strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-' || r == '_' {
+			return r
+		}
+		return '-'
+	}, strings.TrimSpace(slot))
+	return saveFilePrefix + "slot-" + safe + saveFileExtension(format)
+}
 
+// saveGameStateAs saves the current state of the game under the given file name.
+func (m *model) saveGameStateAs(fileName string) tea.Cmd {
+	state := m.game.State()
 	gameState := GameState{
-		Health: 	m.health,
-		Inventory: 	m.inventory,
-		Stats:		m.stats,
+		Health: 	m.game.Health(),
+		Inventory: 	m.game.Inventory(),
+		Stats:		m.game.Stats(),
+		Entities:	state.Entities,
+		Seed:		state.Seed,
+		RNG:		state.RNG,
+		Turn:		state.Turn,
+		Steps:		state.Steps,
+		Cheated:	state.Cheated,
+		Mods:		modRefs(m.mods),
+		Name:		m.playerName,
+		Mode:		m.mode,
+		Ranked:		m.ranked,
 		Timestamp:	time.Now(),
 	}
+	retry := errorAction{"Retry save", func(m *model) tea.Cmd { return m.saveGameStateAs(fileName) }}
+	if gameState.Ranked {
+		if err := signGameState(&gameState); err != nil {
+			return errorCmd(newGameError(errorSaveIO, "Could not sign ranked save", err, retry))
+		}
+	}
 
-	file, err := os.Create(savePath)
+	data, err := encodeGameState(&gameState, m.config.SaveFormat)
 	if err != nil {
-		return func() tea.Msg {
-			return err
-		}
+		return errorCmd(newGameError(errorInternal, "Could not encode save", err))
 	}
-	defer file.Close()
 
-	encoder := json.NewEncoder(file)
-	if err := encoder.Encode(&gameState); err != nil {
-		return func() tea.Msg {
-			return err
+	// Keep the previous contents around so a failed or interrupted write can be recovered
+	if previous, err := m.store.Read(fileName); err == nil {
+		if err := m.store.Write(backupName(fileName), previous); err != nil {
+			return errorCmd(newGameError(errorSaveIO, "Could not back up "+fileName, err, retry))
 		}
 	}
-
-	return func() tea.Msg {
-		return "Game saved successfully"
+	if err := m.store.Write(fileName, data); err != nil {
+		return errorCmd(newGameError(errorSaveIO, "Could not write "+fileName, err, retry))
 	}
+	m.saveFile = fileName
+	m.unsaved = false
+	m.lastSaved = time.Now()
+
+	return logCmd(severitySuccess, categorySave, "Game saved to "+fileName)
 }
 
-// loadGameState loads a selected game state from a file.
+// loadGameState loads a selected game state from the store.
 func (m *model) loadGameState(filePath string) tea.Msg {
-    saveDir, err := getSaveDir() // Get path to save directory
+    runFile := strings.TrimSuffix(filePath, saveBackupExt)
+    retry := errorAction{"Retry load", func(m *model) tea.Cmd { return m.loadAndPlay(filePath) }}
+
+    data, err := m.store.Read(filePath)
     if err != nil {
-		return err
+		return newGameError(errorSaveIO, "Could not read "+filePath, err, retry)
     }
 
-    fullPath := filepath.Join(saveDir, filePath)
-    file, err := os.Open(fullPath)
+    gameState, err := decodeGameState(data)
     if err != nil {
-		return err
+		var actions []errorAction
+		if hasBackup(m.store, filePath) {
+			actions = append(actions, errorAction{"Load backup", func(m *model) tea.Cmd { return m.loadAndPlay(backupName(filePath)) }})
+		}
+		return newGameError(errorCorruptSave, filePath+" is damaged and can't be loaded", err, actions...)
     }
-    defer file.Close()
 
-    var gameState GameState
-    decoder := json.NewDecoder(file)
-    if err := decoder.Decode(&gameState); err != nil {
-		return err
+    game, err := engine.Restore(gameState.engineState())
+    if err != nil {
+		return newGameError(errorCorruptSave, filePath+" is damaged and can't be loaded", err)
     }
 
+    // Permadeath saves are consumed on load so a run can't be rewound by reloading
+    if gameState.isPermadeath() {
+        for _, name := range []string{runFile, backupName(runFile)} {
+            if err := m.store.Delete(name); err != nil && !errors.Is(err, fs.ErrNotExist) {
+                return newGameError(errorSaveIO, "Could not remove "+name, err, retry)
+            }
+        }
+    }
+
+    // Ranked saves that were edited still load, but the run no longer counts
+    ranked := gameState.Ranked && verifyGameState(gameState)
+
     // Apply loaded state
-    m.health = gameState.Health
-    m.inventory = gameState.Inventory
-    m.stats = gameState.Stats
+    m.setGame(game)
+    m.playerName = cmp.Or(gameState.Name, defaultPlayerName)
+    m.mode = gameState.modeOrDefault()
+    m.ranked = ranked
+    m.saveFile = runFile
+    m.unsaved = false
+    m.lastSaved = time.Now()
 
-    return "Game loaded successfully!"
+    var warnings []string
+    if mismatch := modMismatch(gameState.Mods, modRefs(m.mods)); mismatch != "" {
+        warnings = append(warnings, mismatch)
+    }
+    if gameState.Ranked && !ranked {
+        warnings = append(warnings, "Save was modified, this run is now unranked")
+    }
+    if len(warnings) > 0 {
+        return newLogMsg(severityWarning, categorySave, strings.Join(warnings, ". "))
+    }
+    return newLogMsg(severitySuccess, categorySave, "Loaded "+filePath)
+}
+
+// deleteRunSave removes the save belonging to the current run, used when a permadeath run ends.
+func (m *model) deleteRunSave() tea.Cmd {
+	if m.saveFile == "" {
+		return nil
+	}
+	fileName := m.saveFile
+	m.saveFile = ""
+	for _, name := range []string{fileName, backupName(fileName)} {
+		if err := m.store.Delete(name); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			retry := errorAction{"Retry delete", func(m *model) tea.Cmd {
+				m.saveFile = fileName
+				return m.deleteRunSave()
+			}}
+			return errorCmd(newGameError(errorSaveIO, "Could not remove "+name, err, retry))
+		}
+	}
+	return nil
 }
 
+// loadAndPlay loads a save and starts playing it, reporting any failure to the error screen
+func (m *model) loadAndPlay(fileName string) tea.Cmd {
+	msg := m.loadGameState(fileName)
+	if err, ok := msg.(error); ok {
+		return errorCmd(err)
+	}
+	return tea.Batch(m.switchScreen(menuGame), func() tea.Msg { return msg })
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"encoding/json"
+	"maps"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/sss7526/dungeon_crawl/engine"
+)
+
+func TestLegacyItemNamesMigrate(t *testing.T) {
+	tests := []struct {
+		name string
+		save string
+	}{
+		{"before entities", `{"health": 80, "inventory": ["Potion", "Sword", "Shield"],
+			"stats": {"Strength": 10}, "mode": "casual", "timestamp": "2025-01-01T00:00:00Z"}`},
+		{"with entities", `{"health": 80, "inventory": ["Potion", "Sword", "Shield"], "stats": {"Strength": 10},
+			"entities": [{"id": 1, "player": true, "kind": "warrior", "health": {"current": 80, "max": 100},
+				"inventory": {"items": ["Potion", "Sword", "Shield"]}, "stats": {"values": {"Strength": 10}}}],
+			"mode": "casual", "timestamp": "2025-01-01T00:00:00Z"}`},
+	}
+	want := []string{"potion", "sword", "shield"}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var save GameState
+			if err := json.Unmarshal([]byte(tt.save), &save); err != nil {
+				t.Fatal(err)
+			}
+			if problems := validateGameState(save); len(problems) > 0 {
+				t.Fatalf("save doesn't validate: %v", problems)
+			}
+			game, err := engine.Restore(save.engineState())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := game.Inventory(); !slices.Equal(got, want) {
+				t.Errorf("restored inventory %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestListingFieldsComeFromPlayer(t *testing.T) {
+	save := sampleGameState(t, 30)
+	// Older versions only read the top-level copies, which may be stale
+	save.Health, save.Inventory, save.Stats = 1, []string{"Sword"}, map[string]int{"Luck": 1}
+
+	game, err := engine.Restore(save.engineState())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := save.health(); got != game.Health() {
+		t.Errorf("health %.1f, want %.1f", got, game.Health())
+	}
+	if got := save.inventory(); !slices.Equal(got, game.Inventory()) {
+		t.Errorf("inventory %v, want %v", got, game.Inventory())
+	}
+	if got := save.stats(); !maps.Equal(got, game.Stats()) {
+		t.Errorf("stats %v, want %v", got, game.Stats())
+	}
+
+	save.Entities = nil
+	if save.health() != 1 || !slices.Equal(save.inventory(), []string{"sword"}) || save.stats()["Luck"] != 1 {
+		t.Errorf("save without entities lists %.0f, %v, %v, want its top-level fields", save.health(), save.inventory(), save.stats())
+	}
+}
+
+func TestAutosaveOverwritesRunSave(t *testing.T) {
+	content, err := engine.DefaultContent()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// A run loaded from an older save, whose name no new save would pick
+	m := &model{store: newMemoryStore(), mode: modeCasual, config: Config{SaveFormat: formatJSON}, saveFile: "save_1.json"}
+	m.game = engine.New(1, content.Classes[engine.DefaultClass])
+	for range 3 {
+		m.game.Advance()
+		m.autosave()
+	}
+	names, err := m.store.Names()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var saves []string
+	for _, name := range names {
+		if strings.HasPrefix(name, saveFilePrefix) && !strings.HasSuffix(name, saveBackupExt) {
+			saves = append(saves, name)
+		}
+	}
+	if len(saves) != 1 || saves[0] != "save_1.json" || m.saveFile != "save_1.json" {
+		t.Errorf("autosaving three times left saves %v, want only the run's own save_1.json", saves)
+	}
+}
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+)
+
+// keyMap holds every key binding in the game. Screens match input against it
+// and the help overlay renders it, so the two can never disagree.
+type keyMap struct {
+	Up      key.Binding
+	Down    key.Binding
+	Left    key.Binding
+	Right   key.Binding
+	Select  key.Binding
+	Back    key.Binding
+	Save    key.Binding
+	Damage  key.Binding
+	Heal    key.Binding
+	Reload  key.Binding
+	Details key.Binding
+	Help    key.Binding
+	Tab     key.Binding
+	Replay  key.Binding
+	Pause   key.Binding
+	Filter  key.Binding
+	Console key.Binding
+}
+
+func defaultKeyMap() keyMap {
+	return keyMap{
+		Up:      key.NewBinding(key.WithKeys("up"), key.WithHelp("↑", "up")),
+		Down:    key.NewBinding(key.WithKeys("down"), key.WithHelp("↓", "down")),
+		Left:    key.NewBinding(key.WithKeys("left"), key.WithHelp("←", "left")),
+		Right:   key.NewBinding(key.WithKeys("right"), key.WithHelp("→", "right")),
+		Select:  key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "select")),
+		Back:    key.NewBinding(key.WithKeys("esc"), key.WithHelp("esc", "back")),
+		Save:    key.NewBinding(key.WithKeys("ctrl+s"), key.WithHelp("ctrl+s", "quick save")),
+		Damage:  key.NewBinding(key.WithKeys("h"), key.WithHelp("h", "take a hit")),
+		Heal:    key.NewBinding(key.WithKeys("r"), key.WithHelp("r", "rest")),
+		Reload:  key.NewBinding(key.WithKeys("l"), key.WithHelp("l", "reload last save")),
+		Details: key.NewBinding(key.WithKeys("d"), key.WithHelp("d", "toggle details")),
+		Help:    key.NewBinding(key.WithKeys("?"), key.WithHelp("?", "help")),
+		Tab:     key.NewBinding(key.WithKeys("tab"), key.WithHelp("tab", "switch tab")),
+		Replay:  key.NewBinding(key.WithKeys("w"), key.WithHelp("w", "watch replay")),
+		Pause:   key.NewBinding(key.WithKeys(" "), key.WithHelp("space", "play/pause")),
+		Filter:  key.NewBinding(key.WithKeys("/"), key.WithHelp("/", "filter")),
+		Console: key.NewBinding(key.WithKeys("`"), key.WithHelp("`", "developer console")),
+	}
+}
+
+// keyAction names a binding in the key map so it can be listed, rebound and saved
+type keyAction struct {
+	name    string
+	binding *key.Binding
+}
+
+// actions returns every rebindable action in display order
+func (k *keyMap) actions() []keyAction {
+	return []keyAction{
+		{"up", &k.Up},
+		{"down", &k.Down},
+		{"left", &k.Left},
+		{"right", &k.Right},
+		{"select", &k.Select},
+		{"back", &k.Back},
+		{"save", &k.Save},
+		{"damage", &k.Damage},
+		{"heal", &k.Heal},
+		{"reload", &k.Reload},
+		{"details", &k.Details},
+		{"help", &k.Help},
+		{"tab", &k.Tab},
+		{"replay", &k.Replay},
+		{"pause", &k.Pause},
+		{"filter", &k.Filter},
+		{"console", &k.Console},
+	}
+}
+
+// setKeys rebinds a binding, updating its help text to show the new keys
+func setKeys(b *key.Binding, keys ...string) {
+	b.SetKeys(keys...)
+	names := strings.ReplaceAll(strings.Join(keys, "/"), " ", "space") // Space would read as nothing
+	b.SetHelp(names, b.Help().Desc)
+}
+
+// apply overrides bindings with keys loaded from the config, ignoring unknown actions
+func (k *keyMap) apply(overrides map[string][]string) {
+	for _, action := range k.actions() {
+		if keys, ok := overrides[action.name]; ok && len(keys) > 0 {
+			setKeys(action.binding, keys...)
+		}
+	}
+}
+
+// overrides returns the bindings that differ from the defaults, for saving to the config
+func (k *keyMap) overrides() map[string][]string {
+	defaults := defaultKeyMap()
+	defaultActions := defaults.actions()
+	overrides := make(map[string][]string)
+	for i, action := range k.actions() {
+		keys := action.binding.Keys()
+		if strings.Join(keys, " ") != strings.Join(defaultActions[i].binding.Keys(), " ") {
+			overrides[action.name] = keys
+		}
+	}
+	return overrides
+}
+
+// conflict returns the action other than name that already uses keyName
+func (k *keyMap) conflict(name, keyName string) (keyAction, bool) {
+	for _, action := range k.actions() {
+		if action.name == name {
+			continue
+		}
+		for _, bound := range action.binding.Keys() {
+			if bound == keyName {
+				return action, true
+			}
+		}
+	}
+	return keyAction{}, false
+}
+
+// validate reports keys bound to more than one action
+func (k *keyMap) validate() error {
+	seen := make(map[string]string)
+	for _, action := range k.actions() {
+		for _, bound := range action.binding.Keys() {
+			if other, ok := seen[bound]; ok {
+				return fmt.Errorf("key %q is bound to both %s and %s", bound, other, action.name)
+			}
+			seen[bound] = action.name
+		}
+	}
+	return nil
+}
+
+// syncListKeys points a bubbles list's cursor movement at the game's bindings
+func syncListKeys(l *list.Model, keys keyMap) {
+	l.KeyMap.CursorUp = keys.Up
+	l.KeyMap.CursorDown = keys.Down
+}
+
+// withHelp returns a copy of a binding described differently, for screens where
+// the same key does something more specific
+func withHelp(b key.Binding, desc string) key.Binding {
+	b.SetHelp(b.Help().Key, desc)
+	return b
+}
+
+// KeyHelper is implemented by screens that can list the key bindings they respond to
+type KeyHelper interface {
+	KeyBindings(m *model) []key.Binding
+}
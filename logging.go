@@ -0,0 +1,42 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// setupLogging sends structured logs at or above level to the file at path. Bubble Tea
+// owns the terminal, so with no file logs are dropped rather than printed over the screen.
+func setupLogging(path string, level slog.Level) (*os.File, error) {
+	if path == "" {
+		slog.SetDefault(slog.New(slog.DiscardHandler))
+		return nil, nil
+	}
+	f, err := tea.LogToFile(path, "")
+	if err != nil {
+		return nil, err
+	}
+	slog.SetDefault(slog.New(slog.NewTextHandler(f, &slog.HandlerOptions{Level: level})))
+	return f, nil
+}
+
+// level is the log level messages of this severity are written at
+func (s severity) level() slog.Level {
+	switch s {
+	case severityWarning:
+		return slog.LevelWarn
+	case severityError:
+		return slog.LevelError
+	default:
+		return slog.LevelInfo
+	}
+}
+
+// screenName names a screen in logs
+func screenName(screen Screen) string {
+	return strings.TrimPrefix(fmt.Sprintf("%T", screen), "*main.")
+}
@@ -1,21 +1,22 @@
 package main
 
 import (
+	"cmp"
+	"errors"
 	"fmt"
+	"log/slog"
 	"os"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/progress"
+	"github.com/sss7526/dungeon_crawl/engine"
 
 	"golang.org/x/term"
 )
 
 const (
-	startingHealth  = 100.0
-	maxHealth       = 100.0
-	minHealth       = 0
-	healthRegen     = 0.05
 	welcomeDuration = 2 * time.Second
 	defaultWidth	= 10
 	defaultHeight 	= 10
@@ -35,22 +36,48 @@ const (
 	menuGameOver
 	menuLoadGameScreen
 	menuErrorScreen
+	menuNewGame
+	menuSettings
+	menuKeybindings
+	menuThemes
+	menuMessageLog
+	menuReplay
+	menuConsole
 )
 
 type model struct {
 	theme         Theme // Visual configuration for the TUI
 	screens       map[menuChoice]Screen
-	currentScreen Screen
-	health        float64        // Player's health
+	screenStack   []Screen // Active screens, the last one receives input
+	game          *engine.Game   // Rules and state of the current run
 	quitting      bool           // Detect if player wants to quite
 	progress      progress.Model // Progress bar model for health
 	activeMenu    int            // Currently selected toolbar menu in game UI
 	toolbar       []toolbarItem  // The toolbar items
-	inventory     []string       // Example of player inventory
-	stats         map[string]int // Example player stats
 	damageFlash		bool
 	terminalHeight	int
 	terminalWidth 	int
+	mode			gameMode	// Casual or permadeath, chosen at new game
+	saveFile		string		// Save file of the current run, empty until first save or load
+	ranked			bool		// Competitive run whose saves are signed against tampering
+	config			Config		// User configuration loaded at startup
+	store			SaveStore	// Where saves are written and read
+	keys			keyMap		// Key bindings shared by every screen
+	unsaved			bool		// Progress has been made since the last save or load
+	themePath		string		// User theme file being watched for changes, empty for built-ins
+	themeOverride	string		// Theme from --theme, used this session instead of the configured one
+	themeModTime	time.Time	// Modification time of themePath when it was last read
+	startupErrs		[]error		// Problems found while loading settings and content, shown on the first frame
+	content			*engine.Content	// Item, monster, class, spell and loot definitions
+	mods			[]modManifest	// Mods loaded at startup, in load order
+	lastSaved		time.Time	// When the run was last saved, used to schedule autosaves
+	messages		messageLog	// Combat and system messages shown in the game and history screens
+	fixedSeed		*uint64		// Seed for new games from --seed, nil to pick a random one
+	fixedClass		string		// Class for new games from --class, empty for the default
+	fixedName		string		// Name for new games from --name, empty for the default
+	playerName		string		// Name of the player in the current run
+	startCmd		tea.Cmd		// Opens the run started from the command line, if any
+	pendingEvents	[]engine.Event	// Published by the game during an update, waiting to become messages
 }
 
 type Screen interface {
@@ -59,6 +86,22 @@ type Screen interface {
 	View(m *model) string
 }
 
+// Overlay is implemented by modal screens that are drawn on top of the screen beneath them
+type Overlay interface {
+	Overlay() bool
+}
+
+// InputCapturer is implemented by screens that sometimes need every key press, such as
+// text fields, so global shortcuts must not intercept them
+type InputCapturer interface {
+	CapturingInput() bool
+}
+
+// Positioner is implemented by overlays drawn at a fixed position instead of centered
+type Positioner interface {
+	Position(m *model) (x, y int)
+}
+
 func getTerminalSize() (int, int, error) {
 	width, height, err := term.GetSize(int(os.Stdout.Fd()))
 	if err != nil {
@@ -68,8 +111,39 @@ func getTerminalSize() (int, int, error) {
 }
 
 func initialModel() *model {
+	// A broken config still yields usable settings, the problem is reported once the UI is up
+	var startupErrs []error
+	config, err := loadConfig()
+	if err != nil {
+		startupErrs = append(startupErrs, newGameError(errorInternal, "Problem in "+configFileName, err))
+	}
+	mods, err := loadMods()
+	if err != nil {
+		startupErrs = append(startupErrs, newGameError(errorContent, "Problem loading mods", err))
+	}
+	content, mods, err := loadContent(mods) // Mods whose content couldn't be used are left out
+	if err != nil {
+		startupErrs = append(startupErrs, newGameError(errorContent, "Problem in the game content", err))
+	}
+	slog.Info("content loaded", "items", len(content.Items), "monsters", len(content.Monsters),
+		"classes", len(content.Classes), "scripts", len(content.Scripts), "mods", describeMods(modRefs(mods)))
+	for _, err := range startupErrs {
+		slog.Warn("startup problem", "err", err)
+	}
+	applyColorMode(config.ColorMode)
 	theme := newTheme()
 
+	keys := defaultKeyMap()
+	keys.apply(config.Keys)
+	if keys.validate() != nil {
+		keys = defaultKeyMap() // A hand edited config with clashing keys could leave the game unplayable
+	}
+	store, err := newSaveStore(config)
+	if err != nil {
+		slog.Warn("save storage unavailable, using files", "storage", config.Storage, "err", err)
+		store = newFileStore() // Fall back to plain files if the configured backend can't be opened
+	}
+
 	width, height, err := getTerminalSize()
 	if err != nil {
 		width = defaultWidth
@@ -77,17 +151,20 @@ func initialModel() *model {
 	}
 	m := &model{
 		theme:     theme,
-		health:    100,
 		quitting:  false,
 		progress:  theme.ProgressBar,
-		inventory: []string{"Potion", "Sword", "Shield"},
-		stats: map[string]int{
-			"Strength":  10,
-			"Agility":   8,
-			"Intellect": 5,
-		},
 		terminalWidth:	width,
 		terminalHeight: height,
+		mode:			modeCasual,
+		config:			config,
+		store:			store,
+		keys:			keys,
+		startupErrs:	startupErrs,
+		content:		content,
+		mods:			mods,
+	}
+	if source, err := findTheme(config.Theme, mods); err == nil {
+		m.applyTheme(source)
 	}
 	m.screens = map[menuChoice]Screen{
 		menuWelcome:    	NewWelcomeScreen(),
@@ -98,15 +175,92 @@ func initialModel() *model {
 		menuStats:      	NewStatsScreen(),
 		menuLoadGameScreen: NewLoadGameScreen(m),
 		menuErrorScreen: 	NewErrorScreen(),
+		menuNewGame:		NewNewGameScreen(m),
+		menuInventory:		NewInventoryScreen(),
+		menuHelp:			NewHelpScreen(),
+		menuFile:			NewFileMenuScreen(),
+		menuKeybindings:	NewKeybindingsScreen(),
+		menuThemes:			NewThemePickerScreen(),
+		menuSettings:		NewSettingsScreen(),
+		menuMessageLog:		NewMessageLogScreen(),
+		menuReplay:			NewReplayScreen(),
+		menuConsole:		NewConsoleScreen(),
 	}
-	m.currentScreen = m.screens[menuWelcome]
+	m.setGame(engine.New(0, m.playerClass()))
+	m.playerName = defaultPlayerName
+	m.screenStack = []Screen{m.screens[menuWelcome]}
 	m.toolbar = newToolbar(m)
 	return m
 }
 
+// newGame resets the player for a fresh run in the given mode.
+func (m *model) newGame(mode gameMode, ranked bool) {
+	m.setGame(engine.New(m.runSeed(ranked), m.playerClass()))
+	m.playerName = cmp.Or(m.fixedName, defaultPlayerName)
+	m.mode = mode
+	m.ranked = ranked
+	m.saveFile = ""
+	m.unsaved = true
+	m.lastSaved = time.Now()
+	m.activeMenu = 0
+	m.messages = messageLog{}
+	m.log(severityInfo, categorySystem, fmt.Sprintf("A new %s run begins with seed %d", mode, m.game.Seed()))
+}
+
+// runSeed picks the seed for a new run. Ranked runs share the day's seed so everyone
+// plays the same dungeon, otherwise --seed wins over a random one.
+func (m *model) runSeed(ranked bool) uint64 {
+	switch {
+	case ranked:
+		return engine.DailySeed(time.Now())
+	case m.fixedSeed != nil:
+		return *m.fixedSeed
+	default:
+		return engine.RandomSeed()
+	}
+}
+
+// switchScreen replaces the whole screen stack, used when moving between top level areas
 func (m *model) switchScreen(choice menuChoice) tea.Cmd {
-	m.currentScreen = m.screens[choice]
-	return m.currentScreen.Init()
+	m.screenStack = []Screen{m.screens[choice]}
+	slog.Debug("switch screen", "screen", screenName(m.currentScreen()))
+	return m.currentScreen().Init()
+}
+
+// pushScreen opens a screen on top of the current one so it can return with popScreen
+func (m *model) pushScreen(choice menuChoice) tea.Cmd {
+	screen, ok := m.screens[choice]
+	if !ok {
+		return nil // Toolbar entries without a screen yet do nothing
+	}
+	m.screenStack = append(m.screenStack, screen)
+	slog.Debug("push screen", "screen", screenName(screen))
+	return screen.Init()
+}
+
+// popScreen closes the current screen and re-initializes the one that was beneath it
+func (m *model) popScreen() tea.Cmd {
+	if len(m.screenStack) <= 1 {
+		return nil
+	}
+	m.screenStack = m.screenStack[:len(m.screenStack)-1]
+	slog.Debug("pop screen", "screen", screenName(m.currentScreen()))
+	return m.currentScreen().Init()
+}
+
+// currentScreen returns the screen on top of the stack
+func (m *model) currentScreen() Screen {
+	return m.screenStack[len(m.screenStack)-1]
+}
+
+func isCapturingInput(screen Screen) bool {
+	capturer, ok := screen.(InputCapturer)
+	return ok && capturer.CapturingInput()
+}
+
+func isOverlay(screen Screen) bool {
+	overlay, ok := screen.(Overlay)
+	return ok && overlay.Overlay()
 }
 
 type item struct {
@@ -146,7 +300,8 @@ func newToolbar(_ *model) []toolbarItem {
 		newToolbarItem("File", menuFile, nil),
 		newToolbarItem("Stats", menuStats, nil),
 		newToolbarItem("Inventory", menuInventory, nil),
-		newToolbarItem("Help", menuHelp, nil),
+		newToolbarItem("Log", menuMessageLog, nil),
+		newToolbarItem("Help", menuHelp, (*model).openHelp),
 	}
 }
 
@@ -160,34 +315,73 @@ func doTick() tea.Cmd {
 }
 
 func (m *model) Init() tea.Cmd {
-	// Start the game clock
-	return doTick()
+	// Start the game clock and watch the theme file for edits
+	cmds := []tea.Cmd{doTick(), watchTheme(), m.startCmd}
+	switch len(m.startupErrs) {
+	case 0:
+	case 1:
+		cmds = append(cmds, errorCmd(m.startupErrs[0]))
+	default:
+		// The error screen shows one error at a time, so report them together
+		cmds = append(cmds, errorCmd(newGameError(errorInternal, "Problems found at startup", errors.Join(m.startupErrs...))))
+	}
+	return tea.Batch(cmds...)
 }
 
 func (m *model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
-	switch msg.(type) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		// Every screen and overlay lays out against the terminal size, so track it here
+		m.terminalWidth = msg.Width
+		m.terminalHeight = msg.Height
+	case tea.KeyMsg:
+		// Help is available everywhere except while a screen takes raw input, like a text field
+		if key.Matches(msg, m.keys.Help) && !isCapturingInput(m.currentScreen()) && m.currentScreen() != m.screens[menuHelp] {
+			return m, m.openHelp()
+		}
+		if key.Matches(msg, m.keys.Console) && !isCapturingInput(m.currentScreen()) {
+			return m, m.pushScreen(menuConsole)
+		}
+	case logMsg:
+		m.addMessage(msg) // Then let the screen show it too
+	case gameEventMsg:
+		debugEvent(msg.event)
+		m.logEvent(msg.event) // Then let the screen react to it too
+	case themeWatchMsg:
+		return m, tea.Batch(m.reloadThemeIfChanged(), watchTheme())
 	case error:
-		return m, m.switchScreen(menuErrorScreen)
+		slog.Error("error shown", "err", msg)
+		errorScreen := m.screens[menuErrorScreen].(*ErrorScreen)
+		errorScreen.show(msg)
+		m.log(severityError, categorySystem, errorScreen.err.message)
+		if m.currentScreen() == errorScreen {
+			return m, nil // Already open, just show the newest error
+		}
+		return m, m.pushScreen(menuErrorScreen)
 	}
 	// Delegate updates to the current screen's Update method
-	cmd := m.currentScreen.Update(msg, m)
-	return m, cmd
+	cmd := m.currentScreen().Update(msg, m)
+	return m, tea.Batch(cmd, m.flushEvents())
 }
 
 func (m *model) View() string {
-	// Delegate rendering to the current screen's View method
-	return m.currentScreen.View(m)
-}
-
-func main() {
-	for {
-		p := tea.NewProgram(initialModel(), tea.WithAltScreen())
-		if _, err := p.Run(); err != nil {
-			fmt.Println("Error starting application:", err)
-			continue
+	// Render the topmost full screen, then draw any overlays above it on top
+	base := len(m.screenStack) - 1
+	for base > 0 && isOverlay(m.screenStack[base]) {
+		base--
+	}
+	view := m.screenStack[base].View(m)
+	for _, screen := range m.screenStack[base+1:] {
+		fg := screen.View(m)
+		x, y := centerOverlay(view, fg, m.terminalWidth, m.terminalHeight)
+		if positioner, ok := screen.(Positioner); ok {
+			x, y = positioner.Position(m)
 		}
-
-		os.Exit(0)
+		view = placeOverlay(view, fg, x, y, m.terminalHeight)
 	}
+	return view
+}
 
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
 }
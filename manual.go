@@ -0,0 +1,63 @@
+package main
+
+import "strings"
+
+// manualEntry is one topic in the in-game manual
+type manualEntry struct {
+	title string
+	body  string
+}
+
+var manual = []manualEntry{
+	{"Health", "Your health bar runs from red when you are close to death to green at full health. " +
+		"Health slowly regenerates while you explore. Resting restores a chunk of health at once. " +
+		"Without colors the bar fills with = and switches to ! marks with a LOW label when you are in danger."},
+	{"Combat", "Taking a hit costs 10 health and flashes the screen. " +
+		"When your health reaches zero the run is over. With flashing turned off a HIT! banner is shown instead."},
+	{"Items", "Everything you carry is listed in the Inventory menu. " +
+		"Potions restore health, weapons and shields are shown on the Stats screen under Equipment."},
+	{"Saving", "Quick save from inside the game at any time. Casual runs write a new save each time, " +
+		"permadeath runs keep a single save that is deleted when you die and consumed when loaded."},
+	{"Game Modes", "Casual lets you reload your last save after dying. Permadeath gives you one life. " +
+		"Daily Challenge is a ranked permadeath run, edited saves lose their ranking."},
+	{"Seeds", "Every run starts from a seed, shown on the Stats screen. Start the game with --seed <number> " +
+		"to play a run again. Daily Challenge runs share one seed per day."},
+	{"Command Line", "dungeon_crawler new [mode] starts a run straight away and dungeon_crawler continue resumes " +
+		"the latest save. --class, --name, --load, --theme and --save-dir set up the session, and " +
+		"dungeon_crawler -h lists every flag."},
+	{"Developer Console", "Press ` to open the developer console for testing content. It can spawn monsters, " +
+		"give items, teleport, reveal every entity and toggle god mode, type help for the commands. " +
+		"Using it makes a ranked run unranked."},
+	{"Debug Logs", "Start the game with --log-file <path> to write a debug log, and --log-level debug " +
+		"to include every screen change and game event. Attach the log when reporting a bug."},
+	{"Replays", "Saves record every action you take. Press w on a save in the Load Game list to watch the run " +
+		"play back, space pauses, left and right scrub through it and up and down change the speed."},
+	{"Accessibility", "Start the game with --accessible, or set DUNGEON_CRAWLER_ACCESSIBLE, for a plain text mode " +
+		"that works with screen readers. Every action is a typed command, type help to list them."},
+	{"Custom Content", "Items, monsters, classes, spells and loot tables are defined in JSON. Put your own .json " +
+		"files in the content folder next to your saves to add definitions or replace built-in ones with the same id. " +
+		"Problems are reported when the game starts."},
+	{"Scripts", "Content can carry Starlark scripts for bespoke effects. A script runs when an item naming it " +
+		"is used, or on an event such as damage_taken or level_entered. Scripts can call damage, heal, spawn, " +
+		"message, teleport and give, and read health, max_health, turn and depth. A script that runs too long is stopped."},
+	{"Mods", "Install a mod pack as a folder in the mods folder next to your saves. Its mod.json names the mod, " +
+		"its version, the mods it depends on and its load order. Mods can add or replace content and themes, " +
+		"later mods winning. Saves remember their mods and warn when loaded with different ones."},
+	{"Glyph Legend", "@  you\n.  floor\n#  wall\n+  door\n>  stairs down\n<  stairs up\n" +
+		"!  potion\n)  weapon\n[  armor\n$  gold\ng  goblin"},
+}
+
+// searchManual returns the entries whose title or body contain the query, ignoring case
+func searchManual(query string) []manualEntry {
+	query = strings.ToLower(strings.TrimSpace(query))
+	if query == "" {
+		return manual
+	}
+	var matches []manualEntry
+	for _, entry := range manual {
+		if strings.Contains(strings.ToLower(entry.title), query) || strings.Contains(strings.ToLower(entry.body), query) {
+			matches = append(matches, entry)
+		}
+	}
+	return matches
+}
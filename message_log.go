@@ -0,0 +1,126 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// severity ranks how important a log message is
+type severity int
+
+const (
+	severityInfo severity = iota
+	severitySuccess
+	severityWarning
+	severityError
+)
+
+func (s severity) String() string {
+	switch s {
+	case severitySuccess:
+		return "success"
+	case severityWarning:
+		return "warning"
+	case severityError:
+		return "error"
+	default:
+		return "info"
+	}
+}
+
+// messageCategory groups log messages so the history can be filtered
+type messageCategory string
+
+const (
+	categoryCombat messageCategory = "combat"
+	categorySystem messageCategory = "system"
+	categorySave   messageCategory = "save"
+)
+
+var messageCategories = []messageCategory{categoryCombat, categorySystem, categorySave}
+
+// logMsg is a message for the log. Commands return it in place of plain strings.
+type logMsg struct {
+	time     time.Time
+	severity severity
+	category messageCategory
+	text     string
+}
+
+func newLogMsg(sev severity, category messageCategory, text string) logMsg {
+	return logMsg{time: time.Now(), severity: sev, category: category, text: text}
+}
+
+// logCmd returns a command that adds a message to the log
+func logCmd(sev severity, category messageCategory, text string) tea.Cmd {
+	return func() tea.Msg {
+		return newLogMsg(sev, category, text)
+	}
+}
+
+// messageLog keeps the most recent messages, oldest first
+type messageLog struct {
+	entries []logMsg
+}
+
+// add appends a message, dropping the oldest ones beyond limit
+func (l *messageLog) add(entry logMsg, limit int) {
+	l.entries = append(l.entries, entry)
+	if over := len(l.entries) - limit; limit > 0 && over > 0 {
+		l.entries = append([]logMsg(nil), l.entries[over:]...)
+	}
+}
+
+// recent returns up to n of the newest messages, oldest first
+func (l *messageLog) recent(n int) []logMsg {
+	return l.entries[max(len(l.entries)-n, 0):]
+}
+
+// filter returns messages in the category, or every category when empty, containing query
+func (l *messageLog) filter(category messageCategory, query string) []logMsg {
+	query = strings.ToLower(query)
+	var matches []logMsg
+	for _, entry := range l.entries {
+		if category != "" && entry.category != category {
+			continue
+		}
+		if query != "" && !strings.Contains(strings.ToLower(entry.text), query) {
+			continue
+		}
+		matches = append(matches, entry)
+	}
+	return matches
+}
+
+// log adds a message straight to the log from inside an Update
+func (m *model) log(sev severity, category messageCategory, text string) {
+	m.addMessage(newLogMsg(sev, category, text))
+}
+
+// addMessage records a message in the log shown in game and in the debug log
+func (m *model) addMessage(entry logMsg) {
+	slog.Log(context.Background(), entry.severity.level(), entry.text, "category", entry.category)
+	m.messages.add(entry, m.config.MessageLogLength)
+}
+
+// renderLogEntry formats a message in the color of its severity
+func (t Theme) renderLogEntry(entry logMsg, withTime bool) string {
+	text := entry.text
+	if withTime {
+		text = entry.time.Format("15:04:05") + " [" + string(entry.category) + "] " + text
+	}
+	switch entry.severity {
+	case severityError:
+		return t.ErrorStyle.Width(0).Bold(false).Align(0).Render(text)
+	case severityWarning:
+		return t.MenuOptionStyle.PaddingLeft(0).Render(text)
+	case severitySuccess:
+		return t.AttributeStyle.Width(0).Bold(false).Render(text)
+	default:
+		return text
+	}
+}
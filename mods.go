@@ -0,0 +1,172 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+)
+
+const (
+	modDirName      = "mods"
+	modManifestFile = "mod.json"
+)
+
+// modManifest describes a mod pack, read from mod.json in the mod's folder. A mod can
+// carry content files in content/ and theme files in themes/.
+type modManifest struct {
+	Name         string   `json:"name"`
+	Version      string   `json:"version"`
+	Dependencies []string `json:"dependencies"` // Names of mods that must load first
+	LoadOrder    int      `json:"loadOrder"`    // Lower loads first, so later mods override earlier ones
+
+	dir string // Folder the manifest was read from
+}
+
+// modRef identifies a mod in a save
+type modRef struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+func (m modRef) String() string {
+	return m.Name + " " + m.Version
+}
+
+// userModDir returns the directory players install mod packs into
+func userModDir() (string, error) {
+	saveDir, err := getSaveDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(saveDir, modDirName), nil
+}
+
+// loadMods reads every installed mod's manifest and returns the mods in the order they
+// should load. Mods that can't be read or whose dependencies are missing are left out.
+func loadMods() ([]modManifest, error) {
+	dir, err := userModDir()
+	if err != nil {
+		return nil, err
+	}
+	paths, err := filepath.Glob(filepath.Join(dir, "*", modManifestFile))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(paths)
+
+	var mods []modManifest
+	var problems []error
+	seen := map[string]bool{}
+	for _, path := range paths {
+		mod, err := readModManifest(path)
+		switch {
+		case err != nil:
+			problems = append(problems, err)
+		case seen[mod.Name]:
+			problems = append(problems, fmt.Errorf("mod %q is installed twice", mod.Name))
+		default:
+			seen[mod.Name] = true
+			mods = append(mods, mod)
+		}
+	}
+	ordered, err := orderMods(mods)
+	return ordered, errors.Join(append(problems, err)...)
+}
+
+func readModManifest(path string) (modManifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return modManifest{}, err
+	}
+	var mod modManifest
+	if err := json.Unmarshal(data, &mod); err != nil {
+		return modManifest{}, fmt.Errorf("%s: %w", path, err)
+	}
+	if mod.Name == "" || mod.Version == "" {
+		return modManifest{}, fmt.Errorf("%s: a mod needs a name and a version", path)
+	}
+	mod.dir = filepath.Dir(path)
+	return mod, nil
+}
+
+// orderMods sorts mods by load order, moving a mod after its dependencies when needed
+func orderMods(mods []modManifest) ([]modManifest, error) {
+	remaining := slices.Clone(mods)
+	sort.SliceStable(remaining, func(i, j int) bool {
+		if remaining[i].LoadOrder != remaining[j].LoadOrder {
+			return remaining[i].LoadOrder < remaining[j].LoadOrder
+		}
+		return remaining[i].Name < remaining[j].Name
+	})
+
+	var ordered []modManifest
+	loaded := map[string]bool{}
+	for progress := true; progress; {
+		progress = false
+		for i, mod := range remaining {
+			if !slices.ContainsFunc(mod.Dependencies, func(dep string) bool { return !loaded[dep] }) {
+				ordered = append(ordered, mod)
+				loaded[mod.Name] = true
+				remaining = slices.Delete(remaining, i, i+1)
+				progress = true
+				break // Start over so the earliest ready mod always goes next
+			}
+		}
+	}
+
+	installed := map[string]bool{}
+	for _, mod := range mods {
+		installed[mod.Name] = true
+	}
+	var problems []error
+	for _, mod := range remaining {
+		for _, dep := range mod.Dependencies {
+			switch {
+			case !installed[dep]:
+				problems = append(problems, fmt.Errorf("mod %q needs %q, which isn't installed", mod.Name, dep))
+			case !loaded[dep]:
+				problems = append(problems, fmt.Errorf("mod %q needs %q, which couldn't be loaded", mod.Name, dep))
+			}
+		}
+	}
+	return ordered, errors.Join(problems...)
+}
+
+// modFiles lists the JSON files a mod carries in one of its folders
+func (m modManifest) modFiles(folder string) []string {
+	paths, _ := filepath.Glob(filepath.Join(m.dir, folder, "*.json"))
+	sort.Strings(paths)
+	return paths
+}
+
+func modRefs(mods []modManifest) []modRef {
+	refs := make([]modRef, len(mods))
+	for i, mod := range mods {
+		refs[i] = modRef{Name: mod.Name, Version: mod.Version}
+	}
+	return refs
+}
+
+func describeMods(refs []modRef) string {
+	if len(refs) == 0 {
+		return "no mods"
+	}
+	names := make([]string, len(refs))
+	for i, ref := range refs {
+		names[i] = ref.String()
+	}
+	return strings.Join(names, ", ")
+}
+
+// modMismatch describes how a save's mods differ from the active ones, empty if they match
+func modMismatch(saved, active []modRef) string {
+	if slices.Equal(saved, active) {
+		return ""
+	}
+	return fmt.Sprintf("Save was made with %s, the game is running with %s", describeMods(saved), describeMods(active))
+}
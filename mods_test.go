@@ -0,0 +1,108 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestOrderMods(t *testing.T) {
+	mod := func(name string, loadOrder int, deps ...string) modManifest {
+		return modManifest{Name: name, LoadOrder: loadOrder, Dependencies: deps}
+	}
+	tests := []struct {
+		name     string
+		mods     []modManifest
+		want     []string
+		problems []string // Text each error must mention
+	}{
+		{
+			name: "load order then name",
+			mods: []modManifest{mod("b", 1), mod("c", 0), mod("a", 1)},
+			want: []string{"c", "a", "b"},
+		},
+		{
+			name: "dependencies load first",
+			mods: []modManifest{mod("base", 5), mod("addon", 0, "base"), mod("extra", 1, "addon")},
+			want: []string{"base", "addon", "extra"},
+		},
+		{
+			name:     "missing dependency",
+			mods:     []modManifest{mod("base", 0), mod("addon", 1, "lost")},
+			want:     []string{"base"},
+			problems: []string{`"addon" needs "lost", which isn't installed`},
+		},
+		{
+			name:     "dependent of a missing dependency",
+			mods:     []modManifest{mod("addon", 0, "lost"), mod("extra", 1, "addon")},
+			want:     nil,
+			problems: []string{`"addon" needs "lost", which isn't installed`, `"extra" needs "addon", which couldn't be loaded`},
+		},
+		{
+			name:     "cycle",
+			mods:     []modManifest{mod("a", 0, "b"), mod("b", 0, "a"), mod("c", 0)},
+			want:     []string{"c"},
+			problems: []string{`"a" needs "b", which couldn't be loaded`, `"b" needs "a", which couldn't be loaded`},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ordered, err := orderMods(tt.mods)
+			var got []string
+			for _, mod := range ordered {
+				got = append(got, mod.Name)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("loaded %v, want %v", got, tt.want)
+			}
+			if len(tt.problems) == 0 && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			for _, problem := range tt.problems {
+				if err == nil || !strings.Contains(err.Error(), problem) {
+					t.Errorf("error %v, want it to mention %s", err, problem)
+				}
+			}
+		})
+	}
+}
+
+func TestBrokenModContentLeavesModsOut(t *testing.T) {
+	saveDirOverride = t.TempDir()
+	t.Cleanup(func() { saveDirOverride = "" })
+	install := func(name string, files map[string]string) {
+		t.Helper()
+		dir := filepath.Join(saveDirOverride, modDirName, name)
+		files[modManifestFile] = `{"name": "` + name + `", "version": "1.0"}`
+		for file, data := range files {
+			path := filepath.Join(dir, file)
+			if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	// The class parses on its own but carries an item nothing defines
+	install("broken", map[string]string{"content/classes.json": `{"classes": [{"id": "bard", "name": "Bard",
+		"health": 50, "stats": {"Luck": 9}, "items": ["lute"]}]}`})
+	install("palette", map[string]string{})
+
+	mods, err := loadMods()
+	if err != nil {
+		t.Fatal(err)
+	}
+	content, active, err := loadContent(mods)
+	if err == nil {
+		t.Fatal("loading content with a broken mod succeeded, want an error")
+	}
+	if _, ok := content.Classes["bard"]; ok {
+		t.Error("the broken mod's class was loaded, want the built-in content")
+	}
+	if got := modRefs(active); len(got) != 1 || got[0].Name != "palette" {
+		t.Errorf("active mods %v, want only palette, which brings no content", got)
+	}
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// centerOverlay returns the position that centers fg over bg within a width x height area
+func centerOverlay(bg, fg string, width, height int) (int, int) {
+	fgWidth, fgHeight := blockSize(fg)
+	bgHeight := max(strings.Count(bg, "\n")+1, height)
+	return max((width-fgWidth)/2, 0), max((bgHeight-fgHeight)/2, 0)
+}
+
+// blockSize returns the width and height of a rendered block of text
+func blockSize(s string) (int, int) {
+	lines := strings.Split(s, "\n")
+	width := 0
+	for _, line := range lines {
+		width = max(width, ansi.StringWidth(line))
+	}
+	return width, len(lines)
+}
+
+// placeOverlay draws fg on top of bg with its top left corner at x, y, keeping
+// the parts of bg that aren't covered visible around it. bg is padded to height lines.
+func placeOverlay(bg, fg string, x, y, height int) string {
+	bgLines := strings.Split(bg, "\n")
+	fgLines := strings.Split(fg, "\n")
+
+	fgWidth, _ := blockSize(fg)
+	for len(bgLines) < height {
+		bgLines = append(bgLines, "")
+	}
+
+	for i, fgLine := range fgLines {
+		row := y + i
+		if row >= len(bgLines) {
+			bgLines = append(bgLines, "")
+		}
+		bgLine := bgLines[row]
+		if pad := x + fgWidth - ansi.StringWidth(bgLine); pad > 0 {
+			bgLine += strings.Repeat(" ", pad)
+		}
+		left := ansi.Truncate(bgLine, x, "")
+		right := ansi.TruncateLeft(bgLine, x+fgWidth, "")
+		fill := strings.Repeat(" ", fgWidth-ansi.StringWidth(fgLine))
+		bgLines[row] = left + fgLine + fill + right
+	}
+	return strings.Join(bgLines, "\n")
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+)
+
+const (
+	formatJSON = "json" // Plain JSON, readable and hand-editable
+	formatGzip = "gzip" // gzip-compressed JSON
+	formatGob  = "gob"  // Compact binary encoding via encoding/gob
+)
+
+var (
+	gzipMagic = []byte{0x1f, 0x8b}   // Written by compress/gzip at the start of every stream
+	gobMagic  = []byte("DCGOB\x00") // Prepended to gob saves so they can be told apart
+)
+
+// saveFileExtension returns the file extension used for saves in the given format
+func saveFileExtension(format string) string {
+	switch format {
+	case formatGzip:
+		return saveFileExt + ".gz"
+	case formatGob:
+		return ".gob"
+	default:
+		return saveFileExt
+	}
+}
+
+// encodeGameState serializes a game state in the requested format
+func encodeGameState(gameState *GameState, format string) ([]byte, error) {
+	var buf bytes.Buffer
+	switch format {
+	case "", formatJSON:
+		if err := json.NewEncoder(&buf).Encode(gameState); err != nil {
+			return nil, err
+		}
+	case formatGzip:
+		zw := gzip.NewWriter(&buf)
+		if err := json.NewEncoder(zw).Encode(gameState); err != nil {
+			return nil, err
+		}
+		if err := zw.Close(); err != nil {
+			return nil, err
+		}
+	case formatGob:
+		buf.Write(gobMagic)
+		if err := gob.NewEncoder(&buf).Encode(gameState); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unknown save format %q", format)
+	}
+	return buf.Bytes(), nil
+}
+
+// detectSaveFormat inspects the leading bytes of a save to find its encoding
+func detectSaveFormat(data []byte) string {
+	switch {
+	case bytes.HasPrefix(data, gzipMagic):
+		return formatGzip
+	case bytes.HasPrefix(data, gobMagic):
+		return formatGob
+	default:
+		return formatJSON
+	}
+}
+
+// decodeGameState parses a game state, detecting the format from magic bytes
+func decodeGameState(data []byte) (GameState, error) {
+	var gameState GameState
+	switch detectSaveFormat(data) {
+	case formatGzip:
+		zr, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return GameState{}, err
+		}
+		defer zr.Close()
+		if err := json.NewDecoder(zr).Decode(&gameState); err != nil {
+			return GameState{}, err
+		}
+	case formatGob:
+		if err := gob.NewDecoder(bytes.NewReader(data[len(gobMagic):])).Decode(&gameState); err != nil {
+			return GameState{}, err
+		}
+	default:
+		if err := json.NewDecoder(bytes.NewReader(data)).Decode(&gameState); err != nil {
+			return GameState{}, err
+		}
+	}
+	return gameState, nil
+}
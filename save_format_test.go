@@ -0,0 +1,140 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/sss7526/dungeon_crawl/engine"
+)
+
+var saveFormats = []string{formatJSON, formatGzip, formatGob}
+
+// sampleGameState builds a save from a run of the given length, taking a hit and
+// resting in turn so the recording grows with the run
+func sampleGameState(t testing.TB, turns int) GameState {
+	t.Helper()
+	content, err := engine.DefaultContent()
+	if err != nil {
+		t.Fatal(err)
+	}
+	game := engine.New(42, content.Classes[engine.DefaultClass])
+	for turn := range turns {
+		switch turn % 20 {
+		case 0:
+			game.Apply(engine.ActionHit)
+		case 10:
+			game.Apply(engine.ActionRest)
+		}
+		game.Advance()
+	}
+	state := game.State()
+	return GameState{
+		Health:    game.Health(),
+		Inventory: game.Inventory(),
+		Stats:     game.Stats(),
+		Entities:  state.Entities,
+		Seed:      state.Seed,
+		RNG:       state.RNG,
+		Turn:      state.Turn,
+		Steps:     state.Steps,
+		Mode:      modeCasual,
+		Timestamp: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+}
+
+func TestSaveFormatRoundTrip(t *testing.T) {
+	want := sampleGameState(t, 50)
+	for _, format := range saveFormats {
+		t.Run(format, func(t *testing.T) {
+			data, err := encodeGameState(&want, format)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := detectSaveFormat(data); got != format {
+				t.Errorf("detected %s, want %s", got, format)
+			}
+			got, err := decodeGameState(data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("round trip changed the save:\n got %+v\nwant %+v", got, want)
+			}
+		})
+	}
+}
+
+func TestDetectSaveFormat(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+		want string
+	}{
+		{"json", []byte(`{"health": 100}`), formatJSON},
+		{"gzip", []byte{0x1f, 0x8b, 0x08, 0x00}, formatGzip},
+		{"gob", append([]byte("DCGOB\x00"), 0x01), formatGob},
+		{"empty", nil, formatJSON},
+		{"half of the gzip magic", []byte{0x1f}, formatJSON},
+		{"half of the gob magic", []byte("DCG"), formatJSON},
+		{"gob magic only", []byte("DCGOB\x00"), formatGob},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := detectSaveFormat(tt.data); got != tt.want {
+				t.Errorf("detectSaveFormat(%q) = %s, want %s", tt.data, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodeTruncatedSave(t *testing.T) {
+	state := sampleGameState(t, 10)
+	for _, format := range saveFormats {
+		t.Run(format, func(t *testing.T) {
+			data, err := encodeGameState(&state, format)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, n := range []int{0, 1, 2, 6, len(data) / 2} {
+				if _, err := decodeGameState(data[:n]); err == nil {
+					t.Errorf("decoding the first %d of %d bytes succeeded, want an error", n, len(data))
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkEncode(b *testing.B) {
+	state := sampleGameState(b, 1000)
+	for _, format := range saveFormats {
+		b.Run(format, func(b *testing.B) {
+			var data []byte
+			for b.Loop() {
+				var err error
+				if data, err = encodeGameState(&state, format); err != nil {
+					b.Fatal(err)
+				}
+			}
+			b.ReportMetric(float64(len(data)), "bytes/save")
+		})
+	}
+}
+
+func BenchmarkDecode(b *testing.B) {
+	state := sampleGameState(b, 1000)
+	for _, format := range saveFormats {
+		b.Run(format, func(b *testing.B) {
+			data, err := encodeGameState(&state, format)
+			if err != nil {
+				b.Fatal(err)
+			}
+			for b.Loop() {
+				if _, err := decodeGameState(data); err != nil {
+					b.Fatal(err)
+				}
+			}
+			b.ReportMetric(float64(len(data)), "bytes/save")
+		})
+	}
+}
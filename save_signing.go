@@ -0,0 +1,145 @@
+package main
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	installKeyFile = "install.key" // Per-install secret used to sign ranked saves
+	installKeySize = 32
+)
+
+// installSecret returns the per-install signing secret, generating it on first use
+func installSecret() ([]byte, error) {
+	saveDir, err := getSaveDir()
+	if err != nil {
+		return nil, err
+	}
+	path := filepath.Join(saveDir, installKeyFile)
+
+	data, err := os.ReadFile(path)
+	if err == nil {
+		return hex.DecodeString(strings.TrimSpace(string(data)))
+	}
+	if !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+
+	secret := make([]byte, installKeySize)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(path, []byte(hex.EncodeToString(secret)), 0o600); err != nil {
+		return nil, err
+	}
+	return secret, nil
+}
+
+// gameStateMAC computes the HMAC of a game state's canonical JSON, ignoring any existing
+// signature. Canonical JSON leaves out empty values, so the MAC doesn't change when a
+// format decodes empty lists and maps as nil, as gob does.
+func gameStateMAC(g GameState, secret []byte) ([]byte, error) {
+	g.Signature = ""
+	payload, err := canonicalJSON(g)
+	if err != nil {
+		return nil, err
+	}
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(payload)
+	return mac.Sum(nil), nil
+}
+
+// canonicalJSON marshals a value with null, empty and zero-length values left out and
+// object keys sorted, so equal data always gives the same bytes
+func canonicalJSON(v any) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber() // Keep numbers exactly as written
+	var tree any
+	if err := decoder.Decode(&tree); err != nil {
+		return nil, err
+	}
+	return json.Marshal(dropEmpty(tree))
+}
+
+// dropEmpty removes object members with empty values, throughout a decoded JSON tree
+func dropEmpty(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for key, child := range v {
+			if child = dropEmpty(child); isEmpty(child) {
+				delete(v, key)
+			} else {
+				v[key] = child
+			}
+		}
+	case []any:
+		for i, child := range v {
+			v[i] = dropEmpty(child)
+		}
+	}
+	return v
+}
+
+func isEmpty(v any) bool {
+	switch v := v.(type) {
+	case nil:
+		return true
+	case string:
+		return v == ""
+	case []any:
+		return len(v) == 0
+	case map[string]any:
+		return len(v) == 0
+	default:
+		return false
+	}
+}
+
+// signGameState stores an HMAC of the state in its Signature field
+func signGameState(g *GameState) error {
+	secret, err := installSecret()
+	if err != nil {
+		return err
+	}
+	sum, err := gameStateMAC(*g, secret)
+	if err != nil {
+		return err
+	}
+	g.Signature = hex.EncodeToString(sum)
+	return nil
+}
+
+// verifyGameState reports whether a ranked save still matches its signature.
+// Any failure to check counts as tampered, since a ranked run can't be trusted without it.
+func verifyGameState(g GameState) bool {
+	if g.Signature == "" {
+		return false
+	}
+	want, err := hex.DecodeString(g.Signature)
+	if err != nil {
+		return false
+	}
+	secret, err := installSecret()
+	if err != nil {
+		return false
+	}
+	got, err := gameStateMAC(g, secret)
+	if err != nil {
+		return false
+	}
+	return hmac.Equal(want, got)
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"testing"
+)
+
+// rankedGameState is a signed ranked save with empty collections, which gob decodes as nil
+func rankedGameState(t *testing.T) GameState {
+	t.Helper()
+	saveDirOverride = t.TempDir()
+	t.Cleanup(func() { saveDirOverride = "" })
+	state := sampleGameState(t, 30)
+	state.Inventory = []string{}
+	state.Stats = map[string]int{}
+	state.Mode = modePermadeath
+	state.Ranked = true
+	if err := signGameState(&state); err != nil {
+		t.Fatal(err)
+	}
+	return state
+}
+
+func TestSignedSaveVerifies(t *testing.T) {
+	state := rankedGameState(t)
+	for _, format := range saveFormats {
+		t.Run(format, func(t *testing.T) {
+			data, err := encodeGameState(&state, format)
+			if err != nil {
+				t.Fatal(err)
+			}
+			decoded, err := decodeGameState(data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !verifyGameState(decoded) {
+				t.Error("genuine save failed verification")
+			}
+			decoded.Turn++
+			if verifyGameState(decoded) {
+				t.Error("modified save passed verification")
+			}
+		})
+	}
+}
+
+func TestCanonicalJSON(t *testing.T) {
+	type value struct {
+		List []string       `json:"list"`
+		Map  map[string]int `json:"map"`
+		Text string         `json:"text"`
+		Big  uint64         `json:"big"`
+	}
+	empty, err := canonicalJSON(value{List: []string{}, Map: map[string]int{}, Big: 1 << 60})
+	if err != nil {
+		t.Fatal(err)
+	}
+	null, err := canonicalJSON(value{Big: 1 << 60})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"big":1152921504606846976}`; string(empty) != want || string(null) != want {
+		t.Errorf("canonical JSON of empty and nil values is %s and %s, want %s", empty, null, want)
+	}
+}
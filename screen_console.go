@@ -0,0 +1,271 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	gloss "github.com/charmbracelet/lipgloss"
+	"github.com/sss7526/dungeon_crawl/engine"
+)
+
+const (
+	consoleHistory = 100 // Output lines kept
+	consoleLines   = 10  // Output lines shown
+)
+
+// consoleCommand is a developer console command. Cheats change the run or show what the
+// player couldn't know, so using one makes a ranked run unranked. Cheats that change the
+// run also mark it cheated, since its recording no longer replays it.
+type consoleCommand struct {
+	name    string
+	usage   string
+	help    string
+	cheat   bool
+	handler func(m *model, args []string) (string, error)
+}
+
+var consoleCommands []consoleCommand
+
+func init() {
+	// Assigned here because the help command lists consoleCommands itself
+	consoleCommands = []consoleCommand{
+		{"help", "", "List commands", false, consoleHelp},
+		{"spawn", "<monster>", "Spawn a monster where you stand", true, consoleSpawn},
+		{"give", "<item>", "Put an item in your pack", true, consoleGive},
+		{"teleport", "<x> <y> [depth]", "Move to a spot in the dungeon", true, consoleTeleport},
+		{"reveal", "", "List every entity in the dungeon", true, consoleReveal},
+		{"god", "", "Toggle taking no damage", true, consoleGod},
+	}
+}
+
+// ConsoleScreen is the developer console for testing content, opened with the console key
+type ConsoleScreen struct {
+	input  textinput.Model
+	output []string
+}
+
+func NewConsoleScreen() *ConsoleScreen {
+	input := textinput.New()
+	input.Placeholder = "Type help for commands"
+	input.Prompt = "> "
+	return &ConsoleScreen{input: input}
+}
+
+func (s *ConsoleScreen) Init() tea.Cmd {
+	s.input.Reset()
+	return s.input.Focus()
+}
+
+func (s *ConsoleScreen) Update(msg tea.Msg, m *model) tea.Cmd {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return nil
+	}
+	switch {
+	case key.Matches(keyMsg, m.keys.Back, m.keys.Console):
+		s.input.Blur()
+		return m.popScreen()
+	case key.Matches(keyMsg, m.keys.Select):
+		line := strings.TrimSpace(s.input.Value())
+		s.input.Reset()
+		if line != "" {
+			s.print("> " + line)
+			s.print(m.runConsole(line)...)
+		}
+		return nil
+	}
+	var cmd tea.Cmd
+	s.input, cmd = s.input.Update(msg)
+	return cmd
+}
+
+func (s *ConsoleScreen) print(lines ...string) {
+	s.output = append(s.output, lines...)
+	s.output = s.output[max(len(s.output)-consoleHistory, 0):]
+}
+
+// runConsole runs a console line and returns what it printed
+func (m *model) runConsole(line string) []string {
+	args, err := splitConsoleLine(line)
+	if err != nil {
+		return []string{"Error: " + err.Error()}
+	}
+	i := slices.IndexFunc(consoleCommands, func(c consoleCommand) bool { return c.name == strings.ToLower(args[0]) })
+	if i < 0 {
+		return []string{"Unknown command " + args[0] + ", type help for a list"}
+	}
+	command := consoleCommands[i]
+	slog.Info("console command", "line", line)
+	out, err := command.handler(m, args[1:])
+	if err != nil {
+		slog.Warn("console command failed", "line", line, "err", err)
+		return []string{"Error: " + err.Error()}
+	}
+	lines := strings.Split(out, "\n")
+	if command.cheat {
+		m.unsaved = true
+		if m.ranked {
+			m.ranked = false
+			lines = append(lines, "Developer commands were used, this run is now unranked")
+		}
+	}
+	return lines
+}
+
+// splitConsoleLine splits a line into words, keeping words in double quotes together
+// so names with spaces can be given, as in give "Sword of Flame"
+func splitConsoleLine(line string) ([]string, error) {
+	var words []string
+	for i, part := range strings.Split(line, `"`) {
+		if i%2 == 1 {
+			words = append(words, part)
+		} else {
+			words = append(words, strings.Fields(part)...)
+		}
+	}
+	if strings.Count(line, `"`)%2 == 1 {
+		return nil, errors.New("unclosed quote")
+	}
+	if len(words) == 0 {
+		return nil, errors.New("empty command")
+	}
+	return words, nil
+}
+
+// lookupDef finds a definition by ID or by name, ignoring case
+func lookupDef[T any](defs map[string]T, query string, name func(T) string) (string, bool) {
+	for _, id := range slices.Sorted(maps.Keys(defs)) {
+		if strings.EqualFold(id, query) || strings.EqualFold(name(defs[id]), query) {
+			return id, true
+		}
+	}
+	return "", false
+}
+
+func consoleHelp(_ *model, _ []string) (string, error) {
+	lines := make([]string, len(consoleCommands))
+	for i, command := range consoleCommands {
+		lines[i] = strings.TrimSpace(command.name+" "+command.usage) + ": " + command.help
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+func consoleSpawn(m *model, args []string) (string, error) {
+	if len(args) != 1 {
+		return "", errors.New("usage: spawn <monster>")
+	}
+	id, ok := lookupDef(m.content.Monsters, args[0], func(d engine.MonsterDef) string { return d.Name })
+	if !ok {
+		return "", fmt.Errorf("no monster called %s", args[0])
+	}
+	entity, err := m.game.SpawnMonster(id)
+	if err != nil {
+		return "", err
+	}
+	m.game.MarkCheated()
+	return fmt.Sprintf("Spawned %s as entity %d", m.content.Monsters[id].Name, entity), nil
+}
+
+func consoleGive(m *model, args []string) (string, error) {
+	if len(args) != 1 {
+		return "", errors.New(`usage: give <item>, quote names with spaces as in give "Sword of Flame"`)
+	}
+	id, ok := lookupDef(m.content.Items, args[0], func(d engine.ItemDef) string { return d.Name })
+	if !ok {
+		return "", fmt.Errorf("no item called %s", args[0])
+	}
+	if err := m.game.Give(id); err != nil {
+		return "", err
+	}
+	m.game.MarkCheated()
+	return "Gave " + m.content.ItemName(id), nil
+}
+
+func consoleTeleport(m *model, args []string) (string, error) {
+	if len(args) < 2 || len(args) > 3 {
+		return "", errors.New("usage: teleport <x> <y> [depth]")
+	}
+	numbers := make([]int, len(args))
+	for i, arg := range args {
+		n, err := strconv.Atoi(arg)
+		if err != nil {
+			return "", fmt.Errorf("%s is not a number", arg)
+		}
+		numbers[i] = n
+	}
+	to := engine.Position{X: numbers[0], Y: numbers[1], Depth: 1}
+	if position, ok := m.game.World().Positions.Get(m.game.Player()); ok {
+		to.Depth = position.Depth
+	}
+	if len(numbers) == 3 {
+		to.Depth = numbers[2]
+	}
+	if err := m.game.Teleport(to); err != nil {
+		return "", err
+	}
+	m.game.MarkCheated()
+	return fmt.Sprintf("Teleported to %d,%d on depth %d", to.X, to.Y, to.Depth), nil
+}
+
+// consoleReveal lists the world's entities. There is no map to uncover yet, so this
+// shows what a revealed map would.
+func consoleReveal(m *model, _ []string) (string, error) {
+	world := m.game.World()
+	var lines []string
+	for _, entity := range world.Kinds.Entities() {
+		kind, _ := world.Kinds.Get(entity)
+		line := fmt.Sprintf("%d %s", entity, *kind)
+		if name, ok := world.Names.Get(entity); ok {
+			line += fmt.Sprintf(" %q", *name)
+		}
+		if health, ok := world.Healths.Get(entity); ok {
+			line += fmt.Sprintf(" health %.0f/%.0f", health.Current, health.Max)
+		}
+		if position, ok := world.Positions.Get(entity); ok {
+			line += fmt.Sprintf(" at %d,%d depth %d", position.X, position.Y, position.Depth)
+		}
+		lines = append(lines, line)
+	}
+	if len(lines) == 0 {
+		return "Nothing in the dungeon", nil
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+func consoleGod(m *model, _ []string) (string, error) {
+	m.game.SetInvulnerable(!m.game.Invulnerable())
+	m.game.MarkCheated()
+	if m.game.Invulnerable() {
+		return "God mode on, you take no damage", nil
+	}
+	return "God mode off", nil
+}
+
+func (s *ConsoleScreen) Overlay() bool { return true }
+
+func (s *ConsoleScreen) CapturingInput() bool { return true }
+
+// Position draws the console across the top of the screen, like a drop-down terminal
+func (s *ConsoleScreen) Position(m *model) (int, int) {
+	return 0, 0
+}
+
+func (s *ConsoleScreen) KeyBindings(m *model) []key.Binding {
+	return []key.Binding{withHelp(m.keys.Select, "run"), withHelp(m.keys.Console, "close"), withHelp(m.keys.Back, "close")}
+}
+
+func (s *ConsoleScreen) View(m *model) string {
+	lines := []string{m.theme.TitleStyle.Render("Developer Console")}
+	lines = append(lines, s.output[max(len(s.output)-consoleLines, 0):]...)
+	lines = append(lines, s.input.View())
+	content := gloss.JoinVertical(gloss.Left, lines...)
+	return m.theme.BorderStyle.Padding(0, 1).Align(gloss.Left).Width(max(m.terminalWidth-2, 0)).Render(content)
+}
@@ -1,14 +1,51 @@
 package main
 
 import (
+	"fmt"
+	"time"
+
+	"github.com/atotto/clipboard"
+	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
 	gloss "github.com/charmbracelet/lipgloss"
 )
 
-type ErrorScreen struct{}
+type ErrorScreen struct {
+	err         *gameError
+	occurred    time.Time
+	actions     []errorAction // Context actions for the error followed by the standard ones
+	selected    int
+	showDetails bool
+	status      string // Feedback from the last action, e.g. copying details
+}
 
 func NewErrorScreen() *ErrorScreen {
-	return &ErrorScreen{}
+	s := &ErrorScreen{}
+	s.show(nil)
+	return s
+}
+
+// show loads an error into the screen and builds its list of recovery actions
+func (s *ErrorScreen) show(err error) {
+	s.err = asGameError(err)
+	s.occurred = time.Now()
+	s.selected = 0
+	s.showDetails = false
+	s.status = ""
+
+	s.actions = append([]errorAction(nil), s.err.actions...)
+	s.actions = append(s.actions,
+		errorAction{"Copy details", nil},
+		errorAction{"Back", func(m *model) tea.Cmd { return nil }},
+		errorAction{"Main Menu", func(m *model) tea.Cmd { return m.switchScreen(menuMain) }},
+		errorAction{"Quit", func(m *model) tea.Cmd { return m.pushScreen(menuQuitPrompt) }},
+	)
+}
+
+// details returns a plain text report of the error suitable for bug reports
+func (s *ErrorScreen) details() string {
+	return fmt.Sprintf("%s\nCategory: %s\nTime: %s\nError: %v",
+		s.err.message, s.err.category, s.occurred.Format(saveTimestampFmt), s.err.err)
 }
 
 func (s *ErrorScreen) Init() tea.Cmd {
@@ -18,23 +55,63 @@ func (s *ErrorScreen) Init() tea.Cmd {
 func (s *ErrorScreen) Update(msg tea.Msg, m *model) tea.Cmd {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
-		switch msg.Type {
-		case tea.KeyEnter:
-			return m.switchScreen(menuMain)
-		case tea.KeyEsc:
-			return m.switchScreen(menuQuitPrompt)
+		switch {
+		case key.Matches(msg, m.keys.Up):
+			s.selected = max(s.selected-1, 0)
+		case key.Matches(msg, m.keys.Down):
+			s.selected = min(s.selected+1, len(s.actions)-1)
+		case key.Matches(msg, m.keys.Select):
+			action := s.actions[s.selected]
+			if action.handler == nil {
+				s.copyDetails()
+				return nil
+			}
+			// Leave the error screen first so the action runs from where the error happened
+			return tea.Batch(m.popScreen(), action.handler(m))
+		case key.Matches(msg, m.keys.Back):
+			return m.popScreen()
+		case key.Matches(msg, m.keys.Details):
+			s.showDetails = !s.showDetails
 		}
 	}
 	return nil
 }
 
+func (s *ErrorScreen) copyDetails() {
+	if err := clipboard.WriteAll(s.details()); err != nil {
+		s.status = "Clipboard unavailable: " + err.Error()
+		return
+	}
+	s.status = "Details copied to clipboard"
+}
+
+func (s *ErrorScreen) Overlay() bool { return true }
+
+func (s *ErrorScreen) KeyBindings(m *model) []key.Binding {
+	return []key.Binding{m.keys.Up, m.keys.Down, withHelp(m.keys.Select, "run action"), m.keys.Details, m.keys.Back}
+}
+
 func (s *ErrorScreen) View(m *model) string {
-	content := gloss.JoinVertical(
-		gloss.Center,
-		m.theme.ErrorStyle.Render("An error ocurred."),
-		m.theme.ErrorStyle.Render("ESC to Quit"),
-		m.theme.ErrorStyle.Render("Press ENTER for Main Menu"),
-	)
+	lines := []string{
+		m.theme.ErrorStyle.Render(s.err.category.String()),
+		m.theme.ErrorStyle.Bold(false).Render(s.err.message + "\n"),
+	}
+	if s.showDetails {
+		lines = append(lines, m.theme.ErrorStyle.Bold(false).Align(gloss.Left).Render(s.details()+"\n"))
+	}
+	for i, action := range s.actions {
+		if i == s.selected {
+			lines = append(lines, m.theme.ErrorStyle.Render("> "+action.label))
+		} else {
+			lines = append(lines, m.theme.ErrorStyle.Bold(false).Render(action.label))
+		}
+	}
+	if s.status != "" {
+		lines = append(lines, m.theme.ErrorStyle.Bold(false).Render("\n"+s.status))
+	}
+	lines = append(lines, m.theme.ErrorStyle.Bold(false).Render("\nD to toggle details, ESC to go Back"))
+
+	content := gloss.JoinVertical(gloss.Center, lines...)
 	border := m.theme.ErrorBorder.Render(content)
 	return border
-}
\ No newline at end of file
+}
@@ -0,0 +1,172 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	gloss "github.com/charmbracelet/lipgloss"
+)
+
+type fileMenuMode int
+
+const (
+	fileMenuBrowse       fileMenuMode = iota
+	fileMenuSaveAs                    // Typing a slot name
+	fileMenuConfirmLeave              // Warning about unsaved progress before leaving the run
+)
+
+type fileMenuOption struct {
+	label   string
+	handler func(s *FileMenuScreen, m *model) tea.Cmd
+}
+
+// FileMenuScreen is the dropdown opened from the File toolbar entry
+type FileMenuScreen struct {
+	options  []fileMenuOption
+	selected int
+	mode     fileMenuMode
+	slot     textinput.Model
+	status   string
+}
+
+func NewFileMenuScreen() *FileMenuScreen {
+	slot := textinput.New()
+	slot.Placeholder = "slot name"
+	slot.CharLimit = 32
+	slot.Prompt = "Save as: "
+	return &FileMenuScreen{
+		slot: slot,
+		options: []fileMenuOption{
+			{"Save", (*FileMenuScreen).handleSave},
+			{"Save As...", (*FileMenuScreen).handleSaveAs},
+			{"Load", func(_ *FileMenuScreen, m *model) tea.Cmd { return m.pushScreen(menuLoadGameScreen) }},
+			{"Settings", func(_ *FileMenuScreen, m *model) tea.Cmd { return m.pushScreen(menuSettings) }},
+			{"Return to Main Menu", (*FileMenuScreen).handleLeave},
+			{"Quit", func(_ *FileMenuScreen, m *model) tea.Cmd { return m.pushScreen(menuQuitPrompt) }},
+		},
+	}
+}
+
+func (s *FileMenuScreen) Init() tea.Cmd {
+	s.mode = fileMenuBrowse
+	s.status = ""
+	s.slot.Blur()
+	return nil
+}
+
+func (s *FileMenuScreen) handleSave(m *model) tea.Cmd {
+	return tea.Batch(m.popScreen(), m.saveGameState())
+}
+
+func (s *FileMenuScreen) handleSaveAs(m *model) tea.Cmd {
+	if m.mode == modePermadeath {
+		s.status = "Permadeath runs keep a single save"
+		return nil
+	}
+	s.mode = fileMenuSaveAs
+	s.slot.Reset()
+	return s.slot.Focus()
+}
+
+func (s *FileMenuScreen) handleLeave(m *model) tea.Cmd {
+	if m.unsaved {
+		s.mode = fileMenuConfirmLeave
+		return nil
+	}
+	return m.switchScreen(menuMain)
+}
+
+func (s *FileMenuScreen) Update(msg tea.Msg, m *model) tea.Cmd {
+	switch s.mode {
+	case fileMenuSaveAs:
+		return s.updateSaveAs(msg, m)
+	case fileMenuConfirmLeave:
+		if msg, ok := msg.(tea.KeyMsg); ok {
+			switch {
+			case key.Matches(msg, m.keys.Select):
+				return m.switchScreen(menuMain)
+			case key.Matches(msg, m.keys.Back):
+				s.mode = fileMenuBrowse
+			}
+		}
+		return nil
+	}
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, m.keys.Up):
+			s.selected = max(s.selected-1, 0)
+		case key.Matches(msg, m.keys.Down):
+			s.selected = min(s.selected+1, len(s.options)-1)
+		case key.Matches(msg, m.keys.Select):
+			s.status = ""
+			return s.options[s.selected].handler(s, m)
+		case key.Matches(msg, m.keys.Back):
+			return m.popScreen()
+		}
+	}
+	return nil
+}
+
+func (s *FileMenuScreen) updateSaveAs(msg tea.Msg, m *model) tea.Cmd {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch {
+		case key.Matches(msg, m.keys.Select):
+			name := strings.TrimSpace(s.slot.Value())
+			if name == "" {
+				s.status = "Enter a slot name"
+				return nil
+			}
+			return tea.Batch(m.popScreen(), m.saveGameStateAs(slotFileName(name, m.config.SaveFormat)))
+		case key.Matches(msg, m.keys.Back):
+			s.mode = fileMenuBrowse
+			s.slot.Blur()
+			return nil
+		}
+	}
+	var cmd tea.Cmd
+	s.slot, cmd = s.slot.Update(msg)
+	return cmd
+}
+
+func (s *FileMenuScreen) Overlay() bool { return true }
+
+func (s *FileMenuScreen) CapturingInput() bool { return s.mode == fileMenuSaveAs }
+
+// Position drops the menu down directly below the toolbar
+func (s *FileMenuScreen) Position(m *model) (int, int) {
+	return 0, 1
+}
+
+func (s *FileMenuScreen) KeyBindings(m *model) []key.Binding {
+	return []key.Binding{m.keys.Up, m.keys.Down, m.keys.Select, withHelp(m.keys.Back, "close")}
+}
+
+func (s *FileMenuScreen) View(m *model) string {
+	var lines []string
+	switch s.mode {
+	case fileMenuSaveAs:
+		lines = append(lines, s.slot.View())
+	case fileMenuConfirmLeave:
+		lines = append(lines,
+			m.theme.ErrorStyle.Width(0).Render("You have unsaved progress."),
+			"ENTER to leave anyway, ESC to stay",
+		)
+	default:
+		for i, option := range s.options {
+			if i == s.selected {
+				lines = append(lines, m.theme.ToolbarSelected.Render("> "+option.label))
+			} else {
+				lines = append(lines, "  "+option.label)
+			}
+		}
+	}
+	if s.status != "" {
+		lines = append(lines, m.theme.MenuOptionStyle.PaddingLeft(0).Render(s.status))
+	}
+	content := gloss.JoinVertical(gloss.Left, lines...)
+	return m.theme.BorderStyle.Padding(0, 1).Align(gloss.Left).Render(content)
+}
@@ -3,14 +3,19 @@ package main
 import (
 	"fmt"
 	"strings"
-	"math"
 	"time"
 
+	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
 	gloss "github.com/charmbracelet/lipgloss"
+	"github.com/sss7526/dungeon_crawl/engine"
 )
 
-type GameScreen struct{}
+const messagePanelLines = 5
+
+type GameScreen struct {
+	tickID int // Identifies the live tick loop so stale ticks from before a pause are dropped
+}
 
 func NewGameMenuScreen() *GameScreen {
 	return &GameScreen{}
@@ -18,66 +23,103 @@ func NewGameMenuScreen() *GameScreen {
 
 // Command to represent damage flash lifecycle
 type flashCompleteMsg struct{}
-type WindowSizeMsg tea.WindowSizeMsg
 
+// gameTickMsg drives the game clock, tagged with the loop it belongs to
+type gameTickMsg struct {
+	id int
+}
+
+// Init starts a fresh tick loop. It runs whenever the game screen becomes the top
+// screen again, so any tick still in flight from the previous loop is ignored.
 func (s *GameScreen) Init() tea.Cmd {
-	return doTick()
+	s.tickID++
+	return s.tick()
+}
+
+func (s *GameScreen) tick() tea.Cmd {
+	id := s.tickID
+	return tea.Tick(50*time.Millisecond, func(time.Time) tea.Msg {
+		return gameTickMsg{id: id}
+	})
 }
 
 func (s *GameScreen) Update(msg tea.Msg, m *model) tea.Cmd {
 	switch msg := msg.(type) {
 
-	case tea.WindowSizeMsg:
-		return s.updateWindowSize(WindowSizeMsg(msg), m)
-	
-	case TickMsg:
-		if m.health > minHealth {
-			m.health = math.Min(maxHealth, m.health+healthRegen) // Regen health
+	case gameTickMsg:
+		if msg.id != s.tickID {
+			return nil
+		}
+		outcome := m.game.Advance()
+		if outcome.HealthDelta != 0 {
+			m.unsaved = true
+		}
+		if outcome.Died {
+			cmd := m.switchScreen(menuGameOver) // game over if health runs out
+			if m.mode == modePermadeath {
+				return tea.Batch(m.deleteRunSave(), cmd)
+			}
+			return cmd
 		}
-		if m.health <= minHealth {
-			return m.switchScreen(menuGameOver) // game over if health runs out
+		if m.autosaveDue() {
+			return tea.Batch(m.autosave(), s.tick())
 		}
-		return doTick()
+		return s.tick()
 	
+	case gameEventMsg:
+		if _, hit := msg.event.(engine.DamageTaken); hit {
+			return s.triggerFlash(m)
+		}
+
 	case flashCompleteMsg:
 		m.damageFlash = false
 		return nil
 
 	case tea.KeyMsg:
-		switch msg.Type {
-		case tea.KeyCtrlS:
+		switch {
+		case key.Matches(msg, m.keys.Save):
 			return m.saveGameState()
-		case tea.KeyEsc:
-			return m.switchScreen(menuQuitPrompt)
-		case tea.KeyLeft:
+		case key.Matches(msg, m.keys.Back):
+			return m.pushScreen(menuQuitPrompt)
+		case key.Matches(msg, m.keys.Left):
 			m.activeMenu = max(m.activeMenu-1, 0)
-		case tea.KeyRight:
+		case key.Matches(msg, m.keys.Right):
 			m.activeMenu = min(m.activeMenu+1, len(m.toolbar)-1)
-		case tea.KeyEnter:
+		case key.Matches(msg, m.keys.Select):
 			selected := m.toolbar[m.activeMenu]
 			if selected.handler != nil {
 				return selected.handler(m)
 			}
-			m.switchScreen(selected.menuChoice)
-		case tea.KeyRunes:
-			switch string(msg.Runes) {
-			case "h":
-				if m.health > 0 {
-					m.health = math.Max(0, m.health-10)
-					return s.triggerFlash(m)
-				}
-				// m.health = math.Max(0, m.health-10)
-			case "r":
-				m.health = math.Min(maxHealth, m.health+10)
+			return m.pushScreen(selected.menuChoice)
+		case key.Matches(msg, m.keys.Damage):
+			if _, err := m.game.Apply(engine.ActionHit); err == nil {
+				m.unsaved = true
+			}
+		case key.Matches(msg, m.keys.Heal):
+			if _, err := m.game.Apply(engine.ActionRest); err == nil {
+				m.unsaved = true
 			}
 		}
 	}
 	return nil
 }
 
+func (s *GameScreen) KeyBindings(m *model) []key.Binding {
+	return []key.Binding{
+		withHelp(m.keys.Left, "previous menu"),
+		withHelp(m.keys.Right, "next menu"),
+		withHelp(m.keys.Select, "open menu"),
+		m.keys.Save,
+		m.keys.Damage,
+		m.keys.Heal,
+		m.keys.Help,
+		withHelp(m.keys.Back, "quit"),
+	}
+}
+
 func (s *GameScreen) View(m *model) string {
 	var b strings.Builder
-	if m.damageFlash {
+	if m.damageFlash && !m.flashReduced() {
 		return gloss.NewStyle().
 			Background(gloss.Color(m.theme.HealthLow)).
 			Width(m.terminalWidth).
@@ -92,18 +134,40 @@ func (s *GameScreen) View(m *model) string {
 			fmt.Fprint(&b, m.theme.ToolbarStyle.Render(item.label)+" ")
 		}
 	}
-	return b.String() + "\n\nHealth:\n" + m.theme.ProgressBar.ViewAs(float64(m.health)/maxHealth)
+	view := b.String() + "\n\nHealth:\n" + m.theme.HealthBar(m.game.HealthFraction())
+	if m.damageFlash {
+		view += "\n" + m.theme.hitBanner()
+	}
+	return view + "\n\n" + s.messagePanel(m)
 }
 
-func (s *GameScreen) updateWindowSize(msg WindowSizeMsg, m *model) tea.Cmd {
-	m.terminalWidth = msg.Width
-	m.terminalHeight = msg.Height
-	return nil
+// messagePanel shows the newest log messages under the play area
+func (s *GameScreen) messagePanel(m *model) string {
+	lines := []string{}
+	for _, entry := range m.messages.recent(messagePanelLines) {
+		lines = append(lines, m.theme.renderLogEntry(entry, false))
+	}
+	for len(lines) < messagePanelLines {
+		lines = append(lines, "")
+	}
+	return m.theme.BorderStyle.Padding(0, 1).Align(gloss.Left).Width(min(m.terminalWidth, 80) - 2).Render(strings.Join(lines, "\n"))
+}
+
+// flashReduced reports whether damage should be shown as text instead of a full screen flash
+func (m *model) flashReduced() bool {
+	return m.config.NoFlash || m.config.AnimationSpeed == animationOff || m.theme.LowColor
+}
+
+// autosaveDue reports whether the run has unsaved progress older than the autosave cadence
+func (m *model) autosaveDue() bool {
+	cadence := time.Duration(m.config.AutosaveMinutes) * time.Minute
+	return cadence > 0 && m.unsaved && time.Since(m.lastSaved) >= cadence
 }
 
 func (s *GameScreen) triggerFlash(m *model) tea.Cmd {
 	m.damageFlash = true
-	return tea.Tick(time.Millisecond*150, func(_ time.Time) tea.Msg {
+	// Follow the animation speed, but stay up for at least a couple of frames to be seen
+	return tea.Tick(max(m.animationDelay(150*time.Millisecond), 30*time.Millisecond), func(_ time.Time) tea.Msg {
 		return flashCompleteMsg{}
 	})
 }
\ No newline at end of file
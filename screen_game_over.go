@@ -1,6 +1,7 @@
 package main
 
 import (
+	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
 	gloss "github.com/charmbracelet/lipgloss"
 )
@@ -18,20 +19,40 @@ func (s *GameOverScreen) Init() tea.Cmd {
 func (s *GameOverScreen) Update(msg tea.Msg, m *model) tea.Cmd {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
-		switch msg.Type {
-		case tea.KeyEnter:
+		switch {
+		case key.Matches(msg, m.keys.Select):
 			return m.switchScreen(menuMain)
+		case key.Matches(msg, m.keys.Reload) && s.canReload(m):
+			return m.loadAndPlay(m.saveFile)
 		}
 	}
 	return nil
 }
 
+// canReload reports whether the dead run can be resumed from its last save
+func (s *GameOverScreen) canReload(m *model) bool {
+	return m.mode != modePermadeath && m.saveFile != ""
+}
+
+func (s *GameOverScreen) KeyBindings(m *model) []key.Binding {
+	bindings := []key.Binding{withHelp(m.keys.Select, "main menu")}
+	if s.canReload(m) {
+		bindings = append(bindings, m.keys.Reload)
+	}
+	return bindings
+}
+
 func (s *GameOverScreen) View(m *model) string {
-	content := gloss.JoinVertical(
-		gloss.Center,
+	lines := []string{
 		m.theme.TitleStyle.Render("YOU DIED\n"),
-		m.theme.TitleStyle.Foreground(m.theme.Secondary).Render("Press ENTER to return to the Main Menu"),
-	)
+	}
+	if m.mode == modePermadeath {
+		lines = append(lines, m.theme.TitleStyle.Foreground(m.theme.Secondary).Render("Your run has ended for good\n"))
+	} else if s.canReload(m) {
+		lines = append(lines, m.theme.TitleStyle.Foreground(m.theme.Secondary).Render("Press L to reload your last save"))
+	}
+	lines = append(lines, m.theme.TitleStyle.Foreground(m.theme.Secondary).Render("Press ENTER to return to the Main Menu"))
+	content := gloss.JoinVertical(gloss.Center, lines...)
 	border := m.theme.BorderStyle.Render(content)
 	return border
 }
\ No newline at end of file
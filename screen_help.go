@@ -0,0 +1,125 @@
+package main
+
+import (
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	gloss "github.com/charmbracelet/lipgloss"
+)
+
+const (
+	helpTabKeys = iota
+	helpTabManual
+)
+
+// HelpScreen shows the key bindings of the screen it was opened from and the game manual
+type HelpScreen struct {
+	tab      int
+	bindings []key.Binding // Bindings of the screen beneath the overlay
+	help     help.Model
+	search   textinput.Model
+}
+
+func NewHelpScreen() *HelpScreen {
+	search := textinput.New()
+	search.Placeholder = "Search the manual"
+	search.Prompt = "/ "
+	h := help.New()
+	h.ShowAll = true
+	return &HelpScreen{help: h, search: search}
+}
+
+// openHelp shows the help overlay for whatever screen is currently on top
+func (m *model) openHelp() tea.Cmd {
+	s := m.screens[menuHelp].(*HelpScreen)
+	s.bindings = nil
+	if helper, ok := m.currentScreen().(KeyHelper); ok {
+		s.bindings = helper.KeyBindings(m)
+	}
+	s.tab = helpTabKeys
+	return m.pushScreen(menuHelp)
+}
+
+func (s *HelpScreen) Init() tea.Cmd {
+	s.search.Reset()
+	s.search.Blur()
+	return nil
+}
+
+func (s *HelpScreen) Update(msg tea.Msg, m *model) tea.Cmd {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, m.keys.Back):
+			return m.popScreen()
+		case key.Matches(msg, m.keys.Tab):
+			if s.tab == helpTabKeys {
+				s.tab = helpTabManual
+				return s.search.Focus()
+			}
+			s.tab = helpTabKeys
+			s.search.Blur()
+			return nil
+		}
+	}
+	if s.tab == helpTabManual {
+		var cmd tea.Cmd
+		s.search, cmd = s.search.Update(msg)
+		return cmd
+	}
+	return nil
+}
+
+func (s *HelpScreen) Overlay() bool { return true }
+
+func (s *HelpScreen) CapturingInput() bool { return s.tab == helpTabManual }
+
+func (s *HelpScreen) KeyBindings(m *model) []key.Binding {
+	return []key.Binding{m.keys.Tab, withHelp(m.keys.Back, "close")}
+}
+
+func (s *HelpScreen) View(m *model) string {
+	var title, body string
+	switch s.tab {
+	case helpTabManual:
+		title = "Manual"
+		body = s.manualView(m)
+	default:
+		title = "Keys"
+		body = s.keysView()
+	}
+	content := gloss.JoinVertical(gloss.Left,
+		m.theme.TitleStyle.Render("Help: "+title+"\n"),
+		body,
+		m.theme.TitleStyle.Foreground(m.theme.Secondary).Render("\nTAB to switch Keys/Manual, ESC to Close"),
+	)
+	return m.theme.BorderStyle.Align(gloss.Left).Render(content)
+}
+
+func (s *HelpScreen) keysView() string {
+	if len(s.bindings) == 0 {
+		return "No keys on this screen."
+	}
+	// Split bindings into columns of four so long lists stay compact
+	var groups [][]key.Binding
+	for i := 0; i < len(s.bindings); i += 4 {
+		groups = append(groups, s.bindings[i:min(i+4, len(s.bindings))])
+	}
+	return s.help.FullHelpView(groups)
+}
+
+func (s *HelpScreen) manualView(m *model) string {
+	sections := []string{s.search.View(), ""}
+	entries := searchManual(s.search.Value())
+	if len(entries) == 0 {
+		sections = append(sections, "Nothing in the manual matches.")
+	}
+	for _, entry := range entries {
+		sections = append(sections,
+			m.theme.AttributeStyle.Width(0).Render(entry.title),
+			gloss.NewStyle().Width(50).PaddingBottom(1).Render(entry.body),
+		)
+	}
+	return gloss.JoinVertical(gloss.Left, sections...)
+}
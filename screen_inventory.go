@@ -0,0 +1,75 @@
+package main
+
+import (
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	gloss "github.com/charmbracelet/lipgloss"
+)
+
+type InventoryScreen struct {
+	cursor int // Index of the highlighted item
+}
+
+func NewInventoryScreen() *InventoryScreen {
+	return &InventoryScreen{}
+}
+
+func (s *InventoryScreen) Init() tea.Cmd {
+	s.cursor = 0
+	return nil
+}
+
+func (s *InventoryScreen) Update(msg tea.Msg, m *model) tea.Cmd {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, m.keys.Back):
+			return m.popScreen()
+		case key.Matches(msg, m.keys.Up):
+			s.cursor = max(s.cursor-1, 0)
+		case key.Matches(msg, m.keys.Down):
+			s.cursor = min(s.cursor+1, max(len(m.game.Inventory())-1, 0))
+		case key.Matches(msg, m.keys.Select):
+			return s.use(m)
+		}
+	}
+	return nil
+}
+
+// use uses the highlighted item, keeping the cursor in range as items get used up
+func (s *InventoryScreen) use(m *model) tea.Cmd {
+	inventory := m.game.Inventory()
+	if s.cursor >= len(inventory) {
+		return nil
+	}
+	if _, err := m.game.Use(inventory[s.cursor]); err != nil {
+		return logCmd(severityWarning, categoryCombat, err.Error())
+	}
+	m.unsaved = true
+	s.cursor = min(s.cursor, max(len(m.game.Inventory())-1, 0))
+	return nil
+}
+
+func (s *InventoryScreen) Overlay() bool { return true }
+
+func (s *InventoryScreen) KeyBindings(m *model) []key.Binding {
+	return []key.Binding{m.keys.Up, m.keys.Down, withHelp(m.keys.Select, "use"), withHelp(m.keys.Back, "close")}
+}
+
+func (s *InventoryScreen) View(m *model) string {
+	lines := []string{m.theme.TitleStyle.Render("Inventory\n")}
+	inventory := m.game.Inventory()
+	if len(inventory) == 0 {
+		lines = append(lines, m.theme.MenuOptionStyle.Render("Your pack is empty"))
+	}
+	for i, item := range inventory {
+		if i == s.cursor {
+			lines = append(lines, m.theme.ToolbarSelected.Render("> "+m.content.ItemName(item)))
+		} else {
+			lines = append(lines, m.theme.MenuOptionStyle.Render("- "+m.content.ItemName(item)))
+		}
+	}
+	lines = append(lines, m.theme.TitleStyle.Foreground(m.theme.Secondary).Render("\nENTER to Use, ESC to Close"))
+	content := gloss.JoinVertical(gloss.Left, lines...)
+	return m.theme.BorderStyle.Align(gloss.Left).Render(content)
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	gloss "github.com/charmbracelet/lipgloss"
+)
+
+type keybindingsMode int
+
+const (
+	keybindingsBrowse   keybindingsMode = iota
+	keybindingsCapture                  // Waiting for the new key
+	keybindingsConflict                 // New key is taken, asking whether to swap
+)
+
+// KeybindingsScreen lists every action and lets the player rebind it
+type KeybindingsScreen struct {
+	selected int
+	mode     keybindingsMode
+	pending  string    // Key pressed while capturing
+	conflict keyAction // Action already using the pending key
+	status   string
+}
+
+func NewKeybindingsScreen() *KeybindingsScreen {
+	return &KeybindingsScreen{}
+}
+
+func (s *KeybindingsScreen) Init() tea.Cmd {
+	s.mode = keybindingsBrowse
+	s.status = ""
+	return nil
+}
+
+func (s *KeybindingsScreen) Update(msg tea.Msg, m *model) tea.Cmd {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return nil
+	}
+	return s.handleKey(keyMsg, m)
+}
+
+func (s *KeybindingsScreen) handleKey(msg tea.KeyMsg, m *model) tea.Cmd {
+	actions := m.keys.actions()
+
+	switch s.mode {
+	case keybindingsCapture:
+		// Esc always cancels a capture so the player can't lock themselves in
+		if msg.Type == tea.KeyEsc {
+			s.mode = keybindingsBrowse
+			return nil
+		}
+		s.pending = msg.String()
+		action := actions[s.selected]
+		if other, ok := m.keys.conflict(action.name, s.pending); ok {
+			s.conflict = other
+			s.mode = keybindingsConflict
+			return nil
+		}
+		setKeys(action.binding, s.pending)
+		s.mode = keybindingsBrowse
+		return s.persist(m, fmt.Sprintf("%s bound to %s", action.name, s.pending))
+
+	case keybindingsConflict:
+		switch msg.Type {
+		case tea.KeyEnter:
+			// Swap: the other action takes over this action's keys
+			action := actions[s.selected]
+			setKeys(s.conflict.binding, action.binding.Keys()...)
+			setKeys(action.binding, s.pending)
+			s.mode = keybindingsBrowse
+			return s.persist(m, fmt.Sprintf("Swapped %s and %s", action.name, s.conflict.name))
+		case tea.KeyEsc:
+			s.mode = keybindingsBrowse
+		}
+		return nil
+	}
+
+	switch {
+	case key.Matches(msg, m.keys.Up):
+		s.selected = max(s.selected-1, 0)
+	case key.Matches(msg, m.keys.Down):
+		s.selected = min(s.selected+1, len(actions)) // One past the actions is the reset row
+	case key.Matches(msg, m.keys.Select):
+		if s.selected == len(actions) {
+			m.keys = defaultKeyMap()
+			return s.persist(m, "Key bindings reset to defaults")
+		}
+		s.mode = keybindingsCapture
+		s.status = ""
+	case key.Matches(msg, m.keys.Back):
+		return m.popScreen()
+	}
+	return nil
+}
+
+// persist saves the current bindings to the config file
+func (s *KeybindingsScreen) persist(m *model, status string) tea.Cmd {
+	m.config.Keys = m.keys.overrides()
+	if err := saveConfig(m.config); err != nil {
+		s.status = ""
+		return errorCmd(newGameError(errorSaveIO, "Could not save key bindings", err))
+	}
+	s.status = status
+	return nil
+}
+
+func (s *KeybindingsScreen) CapturingInput() bool { return s.mode != keybindingsBrowse }
+
+func (s *KeybindingsScreen) KeyBindings(m *model) []key.Binding {
+	return []key.Binding{m.keys.Up, m.keys.Down, withHelp(m.keys.Select, "rebind"), m.keys.Back}
+}
+
+func (s *KeybindingsScreen) View(m *model) string {
+	actions := m.keys.actions()
+	lines := []string{m.theme.TitleStyle.Render("Key Bindings\n")}
+	for i, action := range actions {
+		row := fmt.Sprintf("%-10s %-14s %s", action.name, strings.Join(action.binding.Keys(), ", "), action.binding.Help().Desc)
+		lines = append(lines, s.renderRow(m, row, i == s.selected))
+	}
+	lines = append(lines, s.renderRow(m, "Reset to defaults", s.selected == len(actions)))
+
+	var prompt string
+	switch s.mode {
+	case keybindingsCapture:
+		prompt = fmt.Sprintf("Press a key for %s, ESC to cancel", actions[s.selected].name)
+	case keybindingsConflict:
+		prompt = fmt.Sprintf("%s is used by %s. ENTER to swap, ESC to cancel", s.pending, s.conflict.name)
+	default:
+		prompt = s.status
+	}
+	if prompt != "" {
+		lines = append(lines, m.theme.TitleStyle.Foreground(m.theme.Secondary).Render("\n"+prompt))
+	}
+
+	content := gloss.JoinVertical(gloss.Left, lines...)
+	return m.theme.BorderStyle.Align(gloss.Left).Render(content)
+}
+
+func (s *KeybindingsScreen) renderRow(m *model, row string, selected bool) string {
+	if selected {
+		return m.theme.ToolbarSelected.Render("> " + row)
+	}
+	return "  " + row
+}
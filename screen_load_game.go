@@ -3,73 +3,96 @@ package main
 import (
 	"fmt"
 
-	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
 )
 
+// saveItem is a save in the list, remembering its file so it can also be replayed
+type saveItem struct {
+	item
+	fileName string
+}
+
 type LoadGameScreen struct {
-	list list.Model
+	list  list.Model
+	model *model
 }
 
 func createLoadHandler(m *model, filePath string) func() tea.Cmd {
 	return func() tea.Cmd {
-		return tea.Batch(func() tea.Msg { return m.loadGameState(filePath) }, m.switchScreen(menuGame))
+		return m.loadAndPlay(filePath)
 	}
 }
 
 func NewLoadGameScreen(m *model) *LoadGameScreen {
+	l := list.New(loadGameItems(m), list.NewDefaultDelegate(), 20, 20)
+	l.Title = "Select Saved Game"
+	return &LoadGameScreen{list: l, model: m}
+}
+
+// loadGameItems builds list items for every save currently on disk
+func loadGameItems(m *model) []list.Item {
 
 	// List saved games
-	saves, err := listSavedGames()
+	saves, err := listSavedGames(m.store)
 	if err != nil {
-		fmt.Println("Failed to list saved games:", err)
 		return nil
 	}
 
 	// Create list items for each save game
 	items := make([]list.Item, len(saves))
 	for i, save := range saves {
-		fullPath := fmt.Sprintf("%s%d%s", saveFilePrefix, save.Timestamp.Unix(), saveFileExt)
-
-		items[i] = newItem(
-			fmt.Sprintf("Save from %s", save.Timestamp.Format(saveTimestampFmt)),
-			fmt.Sprintf("Health: %.0f", save.Health),
-			createLoadHandler(m, fullPath),
-		)
+		description := fmt.Sprintf("Health: %.0f  Mode: %s", save.health(), save.modeOrDefault())
+		if status := save.rankStatus(); status != "" {
+			description += "  " + status
+		}
+		items[i] = saveItem{
+			item: newItem(
+				fmt.Sprintf("Save from %s", save.Timestamp.Format(saveTimestampFmt)),
+				description,
+				createLoadHandler(m, save.fileName),
+			),
+			fileName: save.fileName,
+		}
 	}
-
-	l := list.New(items, list.NewDefaultDelegate(), 20, 20)
-	l.Title = "Select Saved Game"
-	return &LoadGameScreen{list: l}
+	return items
 }
 
 func (s *LoadGameScreen) Init() tea.Cmd {
-	return nil
+	// Saves can be written or consumed while the game runs, so refresh on every visit
+	return s.list.SetItems(loadGameItems(s.model))
 }
 
 func (s *LoadGameScreen) Update(msg tea.Msg, m *model) tea.Cmd {
+	syncListKeys(&s.list, m.keys)
     var cmd tea.Cmd
 	s.list, cmd = s.list.Update(msg)
 	
 	switch msg := msg.(type) {
     case tea.KeyMsg:
-        switch msg.Type {
-        case tea.KeyEnter:
-            if sel, ok := s.list.SelectedItem().(item); ok && sel.handler != nil {
-                return tea.Batch(sel.handler(), func() tea.Msg {
-                    // Provide user feedback after a selection
-                    return "Loading game..."
-                })
+        switch {
+        case key.Matches(msg, m.keys.Select):
+            if sel, ok := s.list.SelectedItem().(saveItem); ok && sel.handler != nil {
+                return sel.handler()
             }
-        case tea.KeyEsc:
-            return m.switchScreen(menuMain)
+        case key.Matches(msg, m.keys.Replay) && s.list.FilterState() != list.Filtering:
+            if sel, ok := s.list.SelectedItem().(saveItem); ok {
+                return m.openReplay(sel.fileName)
+            }
+        case key.Matches(msg, m.keys.Back):
+            return m.popScreen()
         }
-    case string: // Handle feedback messages here
-        s.list.Title = msg // Update the list title with the message
+    case logMsg:
+        return s.list.NewStatusMessage(m.theme.renderLogEntry(msg, false))
     }
     return cmd
 }
 
+func (s *LoadGameScreen) KeyBindings(m *model) []key.Binding {
+	return []key.Binding{m.keys.Up, m.keys.Down, withHelp(m.keys.Select, "load save"), m.keys.Replay, m.keys.Back}
+}
+
 func (s *LoadGameScreen) View(m *model) string {
 	return s.list.View()
 }
\ No newline at end of file
@@ -1,8 +1,9 @@
 package main
 
 import (
-	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
 )
 
 type MainMenuScreen struct {
@@ -23,16 +24,17 @@ func (s *MainMenuScreen) Init() tea.Cmd {
 }
 
 func (s *MainMenuScreen) Update(msg tea.Msg, m *model) tea.Cmd {
+	syncListKeys(&s.list, m.keys)
 	var cmd tea.Cmd
 	var listCmd tea.Cmd
 	s.list, listCmd = s.list.Update(msg)
 	cmd = tea.Batch(cmd, listCmd)
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
-		switch msg.Type {
-		case tea.KeyEsc:
-			m.switchScreen(menuQuitPrompt)
-		case tea.KeyEnter:
+		switch {
+		case key.Matches(msg, m.keys.Back):
+			return m.pushScreen(menuQuitPrompt)
+		case key.Matches(msg, m.keys.Select):
 			if sel, ok := s.list.SelectedItem().(item); ok && sel.handler != nil {
 				return sel.handler()
 			}
@@ -41,18 +43,24 @@ func (s *MainMenuScreen) Update(msg tea.Msg, m *model) tea.Cmd {
 	return cmd
 }
 
+func (s *MainMenuScreen) KeyBindings(m *model) []key.Binding {
+	return []key.Binding{m.keys.Up, m.keys.Down, m.keys.Select, withHelp(m.keys.Back, "quit")}
+}
+
 func (s *MainMenuScreen) View(m *model) string {
 	return "\n" + s.list.View()
 }
 
-func (m *model) handleStartNewGame() tea.Cmd { return m.switchScreen(menuGame) }
-func (m *model) handleLoadGame() tea.Cmd     { return m.switchScreen(menuLoadGameScreen) }
-func (m *model) handleQuit() tea.Cmd         { return m.switchScreen(menuQuitPrompt) }
+func (m *model) handleStartNewGame() tea.Cmd { return m.openNewGame() }
+func (m *model) handleLoadGame() tea.Cmd     { return m.pushScreen(menuLoadGameScreen) }
+func (m *model) handleQuit() tea.Cmd         { return m.pushScreen(menuQuitPrompt) }
+func (m *model) handleSettings() tea.Cmd     { return m.pushScreen(menuSettings) }
 
 func mainMenuOptions(m *model) []list.Item {
 	return []list.Item{
 		newItem("Start New Game", "", m.handleStartNewGame),
 		newItem("Load Game", "", m.handleLoadGame),
+		newItem("Settings", "", m.handleSettings),
 		newItem("Quit", "", m.handleQuit),
 	}
 }
\ No newline at end of file
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	gloss "github.com/charmbracelet/lipgloss"
+)
+
+const messageLogPageLines = 12
+
+// MessageLogScreen shows the full message history with category and text filters
+type MessageLogScreen struct {
+	category int // Index into messageCategories, or -1 for every category
+	offset   int // Lines scrolled up from the newest message
+	filter   textinput.Model
+}
+
+func NewMessageLogScreen() *MessageLogScreen {
+	filter := textinput.New()
+	filter.Placeholder = "Filter messages"
+	filter.Prompt = "/ "
+	return &MessageLogScreen{category: -1, filter: filter}
+}
+
+func (s *MessageLogScreen) Init() tea.Cmd {
+	s.category = -1
+	s.offset = 0
+	s.filter.Reset()
+	s.filter.Blur()
+	return nil
+}
+
+func (s *MessageLogScreen) Update(msg tea.Msg, m *model) tea.Cmd {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return nil
+	}
+	if s.filter.Focused() {
+		switch keyMsg.Type {
+		case tea.KeyEnter, tea.KeyEsc:
+			s.filter.Blur()
+			return nil
+		}
+		var cmd tea.Cmd
+		s.filter, cmd = s.filter.Update(msg)
+		s.offset = 0
+		return cmd
+	}
+	switch {
+	case key.Matches(keyMsg, m.keys.Back):
+		return m.popScreen()
+	case key.Matches(keyMsg, m.keys.Filter):
+		return s.filter.Focus()
+	case key.Matches(keyMsg, m.keys.Tab):
+		s.category++
+		if s.category >= len(messageCategories) {
+			s.category = -1
+		}
+		s.offset = 0
+	case key.Matches(keyMsg, m.keys.Up):
+		s.offset = min(s.offset+1, max(len(s.entries(m))-messageLogPageLines, 0))
+	case key.Matches(keyMsg, m.keys.Down):
+		s.offset = max(s.offset-1, 0)
+	}
+	return nil
+}
+
+func (s *MessageLogScreen) entries(m *model) []logMsg {
+	var category messageCategory
+	if s.category >= 0 {
+		category = messageCategories[s.category]
+	}
+	return m.messages.filter(category, s.filter.Value())
+}
+
+func (s *MessageLogScreen) Overlay() bool { return true }
+
+func (s *MessageLogScreen) CapturingInput() bool { return s.filter.Focused() }
+
+func (s *MessageLogScreen) KeyBindings(m *model) []key.Binding {
+	return []key.Binding{
+		withHelp(m.keys.Up, "older"),
+		withHelp(m.keys.Down, "newer"),
+		withHelp(m.keys.Tab, "category"),
+		m.keys.Filter,
+		withHelp(m.keys.Back, "close"),
+	}
+}
+
+func (s *MessageLogScreen) View(m *model) string {
+	category := "all"
+	if s.category >= 0 {
+		category = string(messageCategories[s.category])
+	}
+	entries := s.entries(m)
+	end := len(entries) - s.offset
+	lines := []string{
+		m.theme.TitleStyle.Render("Message Log\n"),
+		fmt.Sprintf("Category: %s", category),
+		s.filter.View(),
+		"",
+	}
+	if len(entries) == 0 {
+		lines = append(lines, "No messages.")
+	}
+	for _, entry := range entries[max(end-messageLogPageLines, 0):end] {
+		lines = append(lines, m.theme.renderLogEntry(entry, true))
+	}
+	lines = append(lines, m.theme.TitleStyle.Foreground(m.theme.Secondary).Render(fmt.Sprintf("\nTAB for category, %s to filter, ESC to Close", m.keys.Filter.Help().Key)))
+	content := gloss.JoinVertical(gloss.Left, lines...)
+	return m.theme.BorderStyle.Align(gloss.Left).Render(content)
+}
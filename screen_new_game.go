@@ -0,0 +1,72 @@
+package main
+
+import (
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// NewGameScreen lets the player pick a game mode before a run starts
+type NewGameScreen struct {
+	list list.Model
+}
+
+func NewNewGameScreen(m *model) *NewGameScreen {
+	items := []list.Item{
+		newItem("Casual", "Reload from your last save after dying", m.handleNewGame(modeCasual, false)),
+		newItem("Permadeath", "One life. Death deletes the run and loading consumes the save", m.handleNewGame(modePermadeath, false)),
+		newItem("Daily Challenge", "Ranked permadeath run, edited saves become unranked", m.handleNewGame(modePermadeath, true)),
+	}
+	modeList := list.New(items, list.NewDefaultDelegate(), 20, 20)
+	modeList.Title = "Choose Game Mode"
+	modeList.SetShowStatusBar(false)
+	modeList.SetFilteringEnabled(false)
+	return &NewGameScreen{list: modeList}
+}
+
+// openNewGame shows the mode selection with the configured default mode selected
+func (m *model) openNewGame() tea.Cmd {
+	s := m.screens[menuNewGame].(*NewGameScreen)
+	s.list.Select(0)
+	if m.config.DefaultMode == modePermadeath {
+		s.list.Select(1)
+	}
+	return m.pushScreen(menuNewGame)
+}
+
+func (s *NewGameScreen) Init() tea.Cmd {
+	return nil
+}
+
+func (s *NewGameScreen) Update(msg tea.Msg, m *model) tea.Cmd {
+	syncListKeys(&s.list, m.keys)
+	var cmd tea.Cmd
+	s.list, cmd = s.list.Update(msg)
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, m.keys.Back):
+			return m.popScreen()
+		case key.Matches(msg, m.keys.Select):
+			if sel, ok := s.list.SelectedItem().(item); ok && sel.handler != nil {
+				return sel.handler()
+			}
+		}
+	}
+	return cmd
+}
+
+func (s *NewGameScreen) KeyBindings(m *model) []key.Binding {
+	return []key.Binding{m.keys.Up, m.keys.Down, withHelp(m.keys.Select, "start run"), m.keys.Back}
+}
+
+func (s *NewGameScreen) View(m *model) string {
+	return "\n" + s.list.View()
+}
+
+func (m *model) handleNewGame(mode gameMode, ranked bool) func() tea.Cmd {
+	return func() tea.Cmd {
+		m.newGame(mode, ranked)
+		return m.switchScreen(menuGame)
+	}
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
 	gloss "github.com/charmbracelet/lipgloss"
 )
@@ -18,16 +19,22 @@ func (s *QuitPromptScreen) Init() tea.Cmd {
 func (s *QuitPromptScreen) Update(msg tea.Msg, m *model) tea.Cmd {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
-		switch msg.Type {
-		case tea.KeyEnter:
+		switch {
+		case key.Matches(msg, m.keys.Select):
 			return tea.Quit
-		case tea.KeyEsc:
-			return m.switchScreen(menuMain)
+		case key.Matches(msg, m.keys.Back):
+			return m.popScreen()
 		}
 	}
 	return nil
 }
 
+func (s *QuitPromptScreen) Overlay() bool { return true }
+
+func (s *QuitPromptScreen) KeyBindings(m *model) []key.Binding {
+	return []key.Binding{withHelp(m.keys.Select, "quit"), withHelp(m.keys.Back, "cancel")}
+}
+
 func (s *QuitPromptScreen) View(m *model) string {
 	content := gloss.JoinVertical(
 		gloss.Center,
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	gloss "github.com/charmbracelet/lipgloss"
+	"github.com/sss7526/dungeon_crawl/engine"
+)
+
+// replaySpeeds are the turns played per tick, a tick being one turn of live play
+var replaySpeeds = []int{1, 2, 4, 8, 16}
+
+// ReplayScreen plays a recorded run back with play/pause, speed and scrubbing
+type ReplayScreen struct {
+	replayer *engine.Replayer
+	name     string // Save the replay was read from
+	playing  bool
+	speed    int // Index into replaySpeeds
+	tickID   int // Identifies the live tick loop so stale ticks are dropped
+}
+
+// replayTickMsg advances playback, tagged with the loop it belongs to
+type replayTickMsg struct {
+	id int
+}
+
+func NewReplayScreen() *ReplayScreen {
+	return &ReplayScreen{}
+}
+
+// openReplay reads the recording from a save and starts playing it. The save itself is
+// left untouched, so watching a permadeath run doesn't consume it.
+func (m *model) openReplay(fileName string) tea.Cmd {
+	data, err := m.store.Read(fileName)
+	if err != nil {
+		return errorCmd(newGameError(errorSaveIO, "Could not read "+fileName, err))
+	}
+	gameState, err := decodeGameState(data)
+	if err != nil {
+		return errorCmd(newGameError(errorCorruptSave, fileName+" is damaged and can't be replayed", err))
+	}
+	replay := gameState.replay()
+	if replay.Empty() {
+		return logCmd(severityWarning, categorySystem, fileName+" has no recording to replay")
+	}
+	if replay.Cheated {
+		return logCmd(severityWarning, categorySystem, fileName+" was changed by developer commands and can't be replayed")
+	}
+	replayer, err := engine.NewReplayer(replay, m.content)
+	if err != nil {
+		return errorCmd(newGameError(errorContent, fileName+" can't be replayed with the current content", err))
+	}
+	s := m.screens[menuReplay].(*ReplayScreen)
+	s.replayer = replayer
+	s.name = fileName
+	s.playing = true
+	s.speed = 0
+	return m.pushScreen(menuReplay)
+}
+
+func (s *ReplayScreen) Init() tea.Cmd {
+	s.tickID++
+	return s.tick()
+}
+
+func (s *ReplayScreen) tick() tea.Cmd {
+	id := s.tickID
+	return tea.Tick(50*time.Millisecond, func(time.Time) tea.Msg {
+		return replayTickMsg{id: id}
+	})
+}
+
+func (s *ReplayScreen) Update(msg tea.Msg, m *model) tea.Cmd {
+	switch msg := msg.(type) {
+	case replayTickMsg:
+		if msg.id != s.tickID {
+			return nil
+		}
+		if s.playing {
+			for range replaySpeeds[s.speed] {
+				s.replayer.Step()
+			}
+			s.playing = !s.replayer.Done()
+		}
+		return s.tick()
+
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, m.keys.Back):
+			return m.popScreen()
+		case key.Matches(msg, m.keys.Pause):
+			if s.replayer.Done() {
+				s.replayer.Seek(0) // Play again from the start
+			}
+			s.playing = !s.playing
+		case key.Matches(msg, m.keys.Up):
+			s.speed = min(s.speed+1, len(replaySpeeds)-1)
+		case key.Matches(msg, m.keys.Down):
+			s.speed = max(s.speed-1, 0)
+		case key.Matches(msg, m.keys.Left):
+			s.replayer.Seek(max(s.replayer.Turn()-s.scrubStep(), 0))
+		case key.Matches(msg, m.keys.Right):
+			s.replayer.Seek(min(s.replayer.Turn()+s.scrubStep(), s.replayer.Turns()))
+		}
+	}
+	return nil
+}
+
+// scrubStep moves a twentieth of the run per key press
+func (s *ReplayScreen) scrubStep() int {
+	return max(s.replayer.Turns()/20, 1)
+}
+
+func (s *ReplayScreen) KeyBindings(m *model) []key.Binding {
+	return []key.Binding{
+		m.keys.Pause,
+		withHelp(m.keys.Left, "rewind"),
+		withHelp(m.keys.Right, "skip ahead"),
+		withHelp(m.keys.Up, "faster"),
+		withHelp(m.keys.Down, "slower"),
+		withHelp(m.keys.Back, "stop replay"),
+	}
+}
+
+func (s *ReplayScreen) View(m *model) string {
+	game := s.replayer.Game()
+	status := "Paused"
+	switch {
+	case s.replayer.Done():
+		status = "Finished"
+	case s.playing:
+		status = "Playing"
+	}
+	progress := 1.0
+	if s.replayer.Turns() > 0 {
+		progress = float64(s.replayer.Turn()) / float64(s.replayer.Turns())
+	}
+	content := gloss.JoinVertical(gloss.Left,
+		m.theme.TitleStyle.Render("Replay: "+s.name+"\n"),
+		renderKeyValue(m.theme, "Seed", fmt.Sprintf("%d", game.Seed())),
+		"\nHealth:",
+		m.theme.HealthBar(game.HealthFraction()),
+		"",
+		m.theme.ProgressBar.ViewAs(progress),
+		fmt.Sprintf("Turn %d / %d   %s at %dx", s.replayer.Turn(), s.replayer.Turns(), status, replaySpeeds[s.speed]),
+		m.theme.TitleStyle.Foreground(m.theme.Secondary).Render("\nSPACE to play/pause, ←/→ to scrub, ↑/↓ for speed, ESC to Stop"),
+	)
+	return m.theme.BorderStyle.Align(gloss.Left).Render(content)
+}
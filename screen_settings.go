@@ -0,0 +1,196 @@
+package main
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strconv"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	gloss "github.com/charmbracelet/lipgloss"
+)
+
+// settingRow is one line of the settings screen. Rows either cycle through values
+// with left and right, or open another screen with select.
+type settingRow struct {
+	label  string
+	value  func(m *model) string
+	change func(m *model, delta int)
+	open   func(m *model) tea.Cmd
+}
+
+// SettingsScreen edits the preferences stored in the config file
+type SettingsScreen struct {
+	rows     []settingRow
+	selected int
+	status   string
+}
+
+func NewSettingsScreen() *SettingsScreen {
+	return &SettingsScreen{
+		rows: []settingRow{
+			{
+				label: "Theme",
+				value: func(m *model) string { return m.theme.Name },
+				open:  (*model).openThemePicker,
+			},
+			{
+				label: "Key bindings",
+				value: func(*model) string { return "" },
+				open:  func(m *model) tea.Cmd { return m.pushScreen(menuKeybindings) },
+			},
+			{
+				label:  "Animation speed",
+				value:  func(m *model) string { return m.config.AnimationSpeed },
+				change: func(m *model, d int) { m.config.AnimationSpeed = cycle(animationSpeedOptions, m.config.AnimationSpeed, d) },
+			},
+			{
+				label: "Autosave",
+				value: func(m *model) string {
+					if m.config.AutosaveMinutes == 0 {
+						return "off"
+					}
+					return fmt.Sprintf("every %d min", m.config.AutosaveMinutes)
+				},
+				change: func(m *model, d int) { m.config.AutosaveMinutes = cycle(autosaveMinutesOptions, m.config.AutosaveMinutes, d) },
+			},
+			{
+				label:  "Default game mode",
+				value:  func(m *model) string { return string(m.config.DefaultMode) },
+				change: func(m *model, d int) { m.config.DefaultMode = cycle(defaultModeOptions, m.config.DefaultMode, d) },
+			},
+			{
+				label:  "Message log length",
+				value:  func(m *model) string { return strconv.Itoa(m.config.MessageLogLength) },
+				change: func(m *model, d int) { m.config.MessageLogLength = cycle(messageLogLengthOptions, m.config.MessageLogLength, d) },
+			},
+			{
+				label:  "Damage flash",
+				value:  func(m *model) string { return onOff(!m.config.NoFlash) },
+				change: func(m *model, _ int) { m.config.NoFlash = !m.config.NoFlash },
+			},
+			{
+				label: "Color mode",
+				value: func(m *model) string { return m.config.ColorMode },
+				change: func(m *model, d int) {
+					m.config.ColorMode = cycle(colorModeOptions, m.config.ColorMode, d)
+					m.restyle()
+				},
+			},
+			{
+				label:  "Text mode on start",
+				value:  func(m *model) string { return onOff(m.config.Accessible) },
+				change: func(m *model, _ int) { m.config.Accessible = !m.config.Accessible },
+			},
+			{
+				label: "Reset to defaults",
+				value: func(*model) string { return "" },
+				open:  (*model).resetDefaults,
+			},
+		},
+	}
+}
+
+func (s *SettingsScreen) Init() tea.Cmd {
+	s.status = ""
+	return nil
+}
+
+func (s *SettingsScreen) Update(msg tea.Msg, m *model) tea.Cmd {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		row := s.rows[s.selected]
+		switch {
+		case key.Matches(msg, m.keys.Up):
+			s.selected = max(s.selected-1, 0)
+		case key.Matches(msg, m.keys.Down):
+			s.selected = min(s.selected+1, len(s.rows)-1)
+		case key.Matches(msg, m.keys.Left) && row.change != nil:
+			row.change(m, -1)
+			return s.persist(m)
+		case key.Matches(msg, m.keys.Right, m.keys.Select) && row.change != nil:
+			row.change(m, 1)
+			return s.persist(m)
+		case key.Matches(msg, m.keys.Select) && row.open != nil:
+			return row.open(m)
+		case key.Matches(msg, m.keys.Back):
+			return m.popScreen()
+		}
+	}
+	return nil
+}
+
+// resetDefaults restores every preference, including key bindings and theme
+func (m *model) resetDefaults() tea.Cmd {
+	m.config.resetPreferences()
+	m.keys = defaultKeyMap()
+	m.restyle()
+	s := m.screens[menuSettings].(*SettingsScreen)
+	cmd := s.persist(m)
+	s.status = "Settings reset to defaults"
+	return cmd
+}
+
+// restyle rebuilds the theme after a change that affects how colors render
+func (m *model) restyle() {
+	applyColorMode(m.config.ColorMode)
+	source, _ := findTheme(cmp.Or(m.themeOverride, m.config.Theme), m.mods)
+	m.applyTheme(source)
+}
+
+// persist writes the config after a change
+func (s *SettingsScreen) persist(m *model) tea.Cmd {
+	s.status = ""
+	if err := m.config.validate(); err != nil {
+		s.status = err.Error()
+	}
+	if err := saveConfig(m.config); err != nil {
+		return errorCmd(newGameError(errorSaveIO, "Could not save settings", err))
+	}
+	return nil
+}
+
+func (s *SettingsScreen) KeyBindings(m *model) []key.Binding {
+	return []key.Binding{m.keys.Up, m.keys.Down, withHelp(m.keys.Left, "previous value"), withHelp(m.keys.Right, "next value"), withHelp(m.keys.Select, "change or open"), m.keys.Back}
+}
+
+func (s *SettingsScreen) View(m *model) string {
+	lines := []string{m.theme.TitleStyle.Render("Settings\n")}
+	for i, row := range s.rows {
+		value := row.value(m)
+		if row.change != nil {
+			value = "< " + value + " >"
+		} else if row.open != nil && value == "" {
+			value = "..."
+		}
+		line := fmt.Sprintf("%-20s %s", row.label, value)
+		if i == s.selected {
+			lines = append(lines, m.theme.ToolbarSelected.Render("> "+line))
+		} else {
+			lines = append(lines, "  "+line)
+		}
+	}
+	if s.status != "" {
+		lines = append(lines, m.theme.TitleStyle.Foreground(m.theme.Secondary).Render("\n"+s.status))
+	}
+	lines = append(lines, m.theme.TitleStyle.Foreground(m.theme.Secondary).Render("\nChanges are saved as you make them"))
+	content := gloss.JoinVertical(gloss.Left, lines...)
+	return m.theme.BorderStyle.Align(gloss.Left).Render(content)
+}
+
+// cycle returns the option delta steps away from current, wrapping around
+func cycle[T comparable](options []T, current T, delta int) T {
+	i := slices.Index(options, current)
+	if i < 0 {
+		return options[0]
+	}
+	return options[((i+delta)%len(options)+len(options))%len(options)]
+}
+
+func onOff(on bool) string {
+	if on {
+		return "on"
+	}
+	return "off"
+}
@@ -1,9 +1,10 @@
 package main
 
 import (
-	// "fmt"
+	"fmt"
 	// "strings"
 
+	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
 	// "github.com/charmbracelet/bubbles/table"
 	gloss "github.com/charmbracelet/lipgloss"
@@ -22,9 +23,9 @@ func (s *StatsScreen) Init() tea.Cmd {
 func (s *StatsScreen) Update(msg tea.Msg, m *model) tea.Cmd {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
-		switch msg.Type {
-		case tea.KeyEsc:
-			return m.switchScreen(menuGame)
+		switch {
+		case key.Matches(msg, m.keys.Back):
+			return m.popScreen()
 		}
 	}
 	return nil
@@ -107,9 +108,16 @@ func (s *StatsScreen) Update(msg tea.Msg, m *model) tea.Cmd {
 // 		Render(content)
 // }
 
+func (s *StatsScreen) KeyBindings(m *model) []key.Binding {
+	return []key.Binding{m.keys.Back}
+}
+
 func (s *StatsScreen) View(m *model) string {
-	player := newTestPlayer()
-	content := player.View(m.theme)
+	player := newPlayerSheet(m.game, m.content, m.playerName)
+	content := gloss.JoinVertical(gloss.Left,
+		player.View(m.theme),
+		m.theme.BorderStyle.Render(renderKeyValue(m.theme, "Seed", fmt.Sprintf("%d", m.game.Seed()))),
+	)
 
 	return gloss.NewStyle().
 		Width(m.terminalWidth).
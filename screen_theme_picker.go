@@ -0,0 +1,119 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	gloss "github.com/charmbracelet/lipgloss"
+)
+
+// ThemePickerScreen lists available themes and previews each one live as the cursor moves
+type ThemePickerScreen struct {
+	themes   []themeSource
+	selected int
+	original themeSource // Theme active when the picker opened, restored on cancel
+	loadErr  error       // Problems reading user theme files, shown under the list
+}
+
+func NewThemePickerScreen() *ThemePickerScreen {
+	return &ThemePickerScreen{}
+}
+
+// openThemePicker loads the available themes and shows the picker with the active theme selected
+func (m *model) openThemePicker() tea.Cmd {
+	s := m.screens[menuThemes].(*ThemePickerScreen)
+	s.themes, s.loadErr = availableThemes(m.mods)
+	s.selected = 0
+	s.original = s.themes[0]
+	for i, source := range s.themes {
+		if source.spec.Name == m.theme.Name {
+			s.selected = i
+			s.original = source
+		}
+	}
+	return m.pushScreen(menuThemes)
+}
+
+func (s *ThemePickerScreen) Init() tea.Cmd {
+	return nil
+}
+
+func (s *ThemePickerScreen) Update(msg tea.Msg, m *model) tea.Cmd {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, m.keys.Up):
+			s.selected = max(s.selected-1, 0)
+			m.applyTheme(s.themes[s.selected])
+		case key.Matches(msg, m.keys.Down):
+			s.selected = min(s.selected+1, len(s.themes)-1)
+			m.applyTheme(s.themes[s.selected])
+		case key.Matches(msg, m.keys.Select):
+			m.config.Theme = s.themes[s.selected].spec.Name
+			m.themeOverride = "" // Choosing a theme replaces the one from the command line
+			if err := saveConfig(m.config); err != nil {
+				return errorCmd(newGameError(errorSaveIO, "Could not save theme choice", err))
+			}
+			return m.popScreen()
+		case key.Matches(msg, m.keys.Back):
+			m.applyTheme(s.original)
+			return m.popScreen()
+		}
+	}
+	return nil
+}
+
+func (s *ThemePickerScreen) KeyBindings(m *model) []key.Binding {
+	return []key.Binding{withHelp(m.keys.Up, "previous theme"), withHelp(m.keys.Down, "next theme"), withHelp(m.keys.Select, "use theme"), withHelp(m.keys.Back, "cancel")}
+}
+
+func (s *ThemePickerScreen) View(m *model) string {
+	var names []string
+	for i, source := range s.themes {
+		label := source.spec.Name
+		if source.path != "" {
+			label += " (user)"
+		}
+		if i == s.selected {
+			names = append(names, m.theme.ToolbarSelected.Render("> "+label))
+		} else {
+			names = append(names, "  "+label)
+		}
+	}
+	list := m.theme.BorderStyle.Align(gloss.Left).Render(gloss.JoinVertical(gloss.Left, names...))
+
+	lines := []string{
+		m.theme.TitleStyle.Render("Themes\n"),
+		gloss.JoinHorizontal(gloss.Top, list, " ", s.preview(m)),
+	}
+	if s.loadErr != nil {
+		lines = append(lines, m.theme.ErrorStyle.Width(0).Render(fmt.Sprintf("\n%v", s.loadErr)))
+	}
+	lines = append(lines, m.theme.TitleStyle.Foreground(m.theme.Secondary).Render("\nENTER to Use, ESC to Cancel"))
+	return gloss.JoinVertical(gloss.Left, lines...)
+}
+
+// preview renders a sample of every themed element using the active theme
+func (s *ThemePickerScreen) preview(m *model) string {
+	t := m.theme
+	toolbar := t.ToolbarSelected.Render("File") + " " + t.ToolbarStyle.Render("Stats")
+	glyphs := ""
+	for _, glyph := range []string{"@", ".", "#", "+", ">", "!", "$", "g"} {
+		glyphs += t.Glyph(glyph) + " "
+	}
+	content := gloss.JoinVertical(gloss.Left,
+		toolbar,
+		"",
+		t.WelcomeStyle.Width(0).Render("Welcome to the Dungeon!"),
+		t.MenuOptionStyle.PaddingLeft(0).Render("A menu option"),
+		renderKeyValue(t, "Strength", "8"),
+		t.ErrorStyle.Width(0).Render("An error message"),
+		"",
+		t.ProgressBar.ViewAs(0.3),
+		t.ProgressBar.ViewAs(0.9),
+		"",
+		glyphs,
+	)
+	return t.BorderStyle.Align(gloss.Left).Render(content)
+}
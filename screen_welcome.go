@@ -1,6 +1,7 @@
 package main
 
 import (
+	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
 	gloss "github.com/charmbracelet/lipgloss"
 )
@@ -28,17 +29,24 @@ func (s *WelcomeScreen) Update(msg tea.Msg, m *model) tea.Cmd {
 	case TickMsg:
 		if s.animationStep < len(s.message) {
 			s.animationStep++
+			if m.config.AnimationSpeed == animationOff {
+				s.animationStep = len(s.message)
+			}
 			s.animatedMessage = s.message[:s.animationStep]
-			return doTick()
+			return m.animationTick()
 		}
 	case tea.KeyMsg:
-		if msg.Type == tea.KeyEnter {
+		if key.Matches(msg, m.keys.Select) {
 			return m.switchScreen(menuMain)
 		}
 	}
 	return nil
 }
 
+func (s *WelcomeScreen) KeyBindings(m *model) []key.Binding {
+	return []key.Binding{withHelp(m.keys.Select, "continue")}
+}
+
 func (s *WelcomeScreen) View(m *model) string {
 	content := gloss.JoinVertical(
 		gloss.Center,
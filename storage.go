@@ -0,0 +1,154 @@
+package main
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const (
+	storageFile   = "file"   // One JSON file per save in the save directory
+	storageMemory = "memory" // Saves live only as long as the process, used for tests
+	storageBolt   = "bolt"   // All saves in a single embedded database file
+)
+
+// SaveStore is where serialized game states are kept. Implementations only deal
+// with names and raw bytes, encoding is handled by the game state code.
+type SaveStore interface {
+	Names() ([]string, error)
+	Read(name string) ([]byte, error)
+	Write(name string, data []byte) error
+	Delete(name string) error
+	Close() error
+}
+
+// newSaveStore returns the storage backend selected by the config
+func newSaveStore(cfg Config) (SaveStore, error) {
+	switch cfg.Storage {
+	case "", storageFile:
+		return newFileStore(), nil
+	case storageMemory:
+		return newMemoryStore(), nil
+	case storageBolt:
+		return newBoltStore()
+	default:
+		return nil, fmt.Errorf("unknown storage backend %q", cfg.Storage)
+	}
+}
+
+// fileStore keeps each save as a file in the save directory
+type fileStore struct{}
+
+func newFileStore() *fileStore {
+	return &fileStore{}
+}
+
+func (s *fileStore) Names() ([]string, error) {
+	saveDir, err := getSaveDir()
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := os.ReadDir(saveDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, entry := range entries {
+		if entry.IsDir() || !isSaveName(entry.Name()) {
+			continue
+		}
+		names = append(names, entry.Name())
+	}
+	return names, nil
+}
+
+func (s *fileStore) Read(name string) ([]byte, error) {
+	saveDir, err := getSaveDir()
+	if err != nil {
+		return nil, err
+	}
+	return os.ReadFile(filepath.Join(saveDir, name))
+}
+
+func (s *fileStore) Write(name string, data []byte) error {
+	saveDir, err := getSaveDir()
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(saveDir, name), data, 0o600)
+}
+
+func (s *fileStore) Delete(name string) error {
+	saveDir, err := getSaveDir()
+	if err != nil {
+		return err
+	}
+	return os.Remove(filepath.Join(saveDir, name))
+}
+
+func (s *fileStore) Close() error {
+	return nil
+}
+
+// memoryStore keeps saves in a map, nothing touches the disk
+type memoryStore struct {
+	mu    sync.Mutex
+	saves map[string][]byte
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{saves: make(map[string][]byte)}
+}
+
+func (s *memoryStore) Names() ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	names := make([]string, 0, len(s.saves))
+	for name := range s.saves {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+func (s *memoryStore) Read(name string) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, ok := s.saves[name]
+	if !ok {
+		return nil, fmt.Errorf("read %s: %w", name, fs.ErrNotExist)
+	}
+	return append([]byte(nil), data...), nil
+}
+
+func (s *memoryStore) Write(name string, data []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.saves[name] = append([]byte(nil), data...)
+	return nil
+}
+
+func (s *memoryStore) Delete(name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.saves[name]; !ok {
+		return fmt.Errorf("delete %s: %w", name, fs.ErrNotExist)
+	}
+	delete(s.saves, name)
+	return nil
+}
+
+func (s *memoryStore) Close() error {
+	return nil
+}
+
+// isSaveName reports whether a file name looks like a save written by the game
+func isSaveName(name string) bool {
+	return strings.HasPrefix(name, saveFilePrefix)
+}
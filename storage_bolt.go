@@ -0,0 +1,87 @@
+package main
+
+import (
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+const (
+	boltFileName = "saves.db"
+	boltTimeout  = time.Second // How long to wait if another instance holds the database
+)
+
+var boltSavesBucket = []byte("saves")
+
+// boltStore keeps every save in a single bbolt database in the save directory,
+// which scales better than a directory of files for players with many runs
+type boltStore struct {
+	db *bolt.DB
+}
+
+func newBoltStore() (*boltStore, error) {
+	saveDir, err := getSaveDir()
+	if err != nil {
+		return nil, err
+	}
+	db, err := bolt.Open(filepath.Join(saveDir, boltFileName), 0o600, &bolt.Options{Timeout: boltTimeout})
+	if err != nil {
+		return nil, err
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(boltSavesBucket)
+		return err
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &boltStore{db: db}, nil
+}
+
+func (s *boltStore) Names() ([]string, error) {
+	var names []string
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(boltSavesBucket).ForEach(func(k, _ []byte) error {
+			names = append(names, string(k))
+			return nil
+		})
+	})
+	return names, err
+}
+
+func (s *boltStore) Read(name string) ([]byte, error) {
+	var data []byte
+	err := s.db.View(func(tx *bolt.Tx) error {
+		v := tx.Bucket(boltSavesBucket).Get([]byte(name))
+		if v == nil {
+			return fmt.Errorf("read %s: %w", name, fs.ErrNotExist)
+		}
+		data = append([]byte(nil), v...) // Values are only valid inside the transaction
+		return nil
+	})
+	return data, err
+}
+
+func (s *boltStore) Write(name string, data []byte) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(boltSavesBucket).Put([]byte(name), data)
+	})
+}
+
+func (s *boltStore) Delete(name string) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(boltSavesBucket)
+		if b.Get([]byte(name)) == nil {
+			return fmt.Errorf("delete %s: %w", name, fs.ErrNotExist)
+		}
+		return b.Delete([]byte(name))
+	})
+}
+
+func (s *boltStore) Close() error {
+	return s.db.Close()
+}
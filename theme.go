@@ -6,13 +6,18 @@ import (
 )
 
 type Theme struct {
+	Name     string
+	LowColor bool // Terminal has 16 colors or fewer, so color-only cues need a fallback
+
 	// Colors
 	Primary    gloss.AdaptiveColor
 	Secondary  gloss.AdaptiveColor
 	Error	   gloss.AdaptiveColor
 	Selected   gloss.AdaptiveColor
+	Accent     gloss.AdaptiveColor
 	HealthLow  gloss.Color
 	HealthHigh gloss.Color
+	Glyphs     map[string]gloss.Color // Colors for map glyphs, keyed by the glyph
 
 	// Styles
 	TitleStyle      gloss.Style
@@ -29,14 +34,20 @@ type Theme struct {
 	ProgressBar progress.Model
 }
 
-// newTheme initializes and returns a Theme instance.
+// newTheme initializes and returns the default Theme instance.
 func newTheme() Theme {
-	primaryColor := gloss.AdaptiveColor{Light: "#FF5733", Dark: "#AE81FC"}
-	secondaryColor := gloss.AdaptiveColor{Light: "#FFD700", Dark: "#FF9700"}
-	errorColor := gloss.AdaptiveColor{Light: "#D70000", Dark: "#FF5C5C"}
-	selectedColor := gloss.AdaptiveColor{Light: "#00C9A7", Dark: "#1B998B"}
-	healthLowColor := gloss.Color("#FF3E41")
-	healthHighColor := gloss.Color("#00FF00")
+	return newThemeFromSpec(defaultThemeSpec())
+}
+
+// newThemeFromSpec builds a Theme from its data file description.
+func newThemeFromSpec(spec ThemeSpec) Theme {
+	primaryColor := spec.Primary.color()
+	secondaryColor := spec.Secondary.color()
+	errorColor := spec.Error.color()
+	selectedColor := spec.Selected.color()
+	accentColor := spec.Accent.color()
+	healthLowColor := gloss.Color(spec.HealthLow)
+	healthHighColor := gloss.Color(spec.HealthHigh)
 
 	titleStyle := gloss.NewStyle().
 		Align(gloss.Center).
@@ -47,55 +58,81 @@ func newTheme() Theme {
 	errorStyle := titleStyle.Foreground(errorColor)
 
 	borderStyle := gloss.NewStyle().
-		Border(gloss.RoundedBorder()).
+		Border(spec.border()).
 		BorderForeground(primaryColor).
 		Padding(1, 2).
 		Align(gloss.Center)
 	
 	errorBorder := borderStyle.BorderForeground(errorColor)
 
+	lowColor := isLowColor()
+	progressBar := progress.New(progress.WithGradient(spec.HealthLow, spec.HealthHigh), progress.WithColorProfile(gloss.ColorProfile()))
+	if lowColor {
+		// Gradients turn to noise with few colors, a solid fill with a pattern stays readable
+		progressBar = progress.New(progress.WithSolidFill(spec.HealthHigh), progress.WithFillCharacters('#', '-'), progress.WithColorProfile(gloss.ColorProfile()))
+	}
+
+	glyphs := make(map[string]gloss.Color, len(spec.Glyphs))
+	for glyph, color := range spec.Glyphs {
+		glyphs[glyph] = gloss.Color(color)
+	}
+
 	return Theme{
+		Name:     spec.Name,
+		LowColor: lowColor,
+
 		// Adaptive colors for light and dark modes
 		Primary:    primaryColor,
 		Secondary:  secondaryColor,
 		Error:		errorColor,
 		Selected:   selectedColor,
+		Accent:     accentColor,
 		HealthLow:  healthLowColor,
 		HealthHigh: healthHighColor,
+		Glyphs:     glyphs,
 
 		// Styles
 		TitleStyle: titleStyle,
 		ErrorStyle: errorStyle,
 
 		WelcomeStyle: gloss.NewStyle().
-			Foreground(gloss.AdaptiveColor{Light: "#00DFA2", Dark: "#3EC5F8"}).
+			Foreground(accentColor).
 			Align(gloss.Center).
 			Width(50).
 			Bold(true),
 
 		MenuOptionStyle: gloss.NewStyle().
 			PaddingLeft(4).
-			Foreground(gloss.AdaptiveColor{Light: "#FFD700", Dark: "#FF9700"}),
+			Foreground(secondaryColor),
 
 		ToolbarStyle: gloss.NewStyle().
-			Background(gloss.AdaptiveColor{Light: "#FF5733", Dark: "#AE81FC"}).
-			Foreground(gloss.Color("#FFFFFF")).
+			Background(primaryColor).
+			Foreground(gloss.Color(spec.ToolbarText)).
 			Padding(0, 1),
 
 		ToolbarSelected: gloss.NewStyle().
-			Background(gloss.AdaptiveColor{Light: "#00C9A7", Dark: "#1B998B"}).
+			Background(selectedColor).
 			Underline(true).
 			Bold(true),
 
 		AttributeStyle: gloss.NewStyle().
 			Bold(true).
 			Width(15).
-			Foreground(gloss.AdaptiveColor{Light: "#00DFA2", Dark: "#3EC5F8"}),
+			Foreground(accentColor),
 
 		BorderStyle: borderStyle,
 		ErrorBorder: errorBorder,
 
 		// Progress Bar
-		ProgressBar: progress.New(progress.WithGradient("#FF3E41", "#00FF00")),
+		ProgressBar: progressBar,
+	}
+}
+
+// Glyph renders a map glyph in its theme color, or plain if the theme doesn't color it
+func (t Theme) Glyph(glyph string) string {
+	color, ok := t.Glyphs[glyph]
+	if !ok {
+		return glyph
 	}
-}
\ No newline at end of file
+	return gloss.NewStyle().Foreground(color).Render(glyph)
+}
@@ -0,0 +1,279 @@
+package main
+
+import (
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	gloss "github.com/charmbracelet/lipgloss"
+)
+
+const (
+	themeDirName       = "themes"
+	defaultThemeName   = "default"
+	themeWatchInterval = time.Second
+)
+
+//go:embed themes/*.json
+var builtinThemes embed.FS
+
+var colorPattern = regexp.MustCompile(`^(#[0-9A-Fa-f]{6}|#[0-9A-Fa-f]{3}|[0-9]{1,3})$`)
+
+// colorSpec is a color that can differ between light and dark terminals
+type colorSpec struct {
+	Light string `json:"light"`
+	Dark  string `json:"dark"`
+}
+
+func (c colorSpec) color() gloss.AdaptiveColor {
+	return gloss.AdaptiveColor{Light: c.Light, Dark: c.Dark}
+}
+
+// ThemeSpec is the data file form of a Theme
+type ThemeSpec struct {
+	Name        string            `json:"name"`
+	Primary     colorSpec         `json:"primary"`
+	Secondary   colorSpec         `json:"secondary"`
+	Error       colorSpec         `json:"error"`
+	Selected    colorSpec         `json:"selected"`
+	Accent      colorSpec         `json:"accent"`
+	ToolbarText string            `json:"toolbarText"`
+	HealthLow   string            `json:"healthLow"`
+	HealthHigh  string            `json:"healthHigh"`
+	Border      string            `json:"border"` // rounded, normal, thick, double or hidden
+	Glyphs      map[string]string `json:"glyphs"`
+}
+
+func defaultThemeSpec() ThemeSpec {
+	return ThemeSpec{
+		Name:        defaultThemeName,
+		Primary:     colorSpec{Light: "#FF5733", Dark: "#AE81FC"},
+		Secondary:   colorSpec{Light: "#FFD700", Dark: "#FF9700"},
+		Error:       colorSpec{Light: "#D70000", Dark: "#FF5C5C"},
+		Selected:    colorSpec{Light: "#00C9A7", Dark: "#1B998B"},
+		Accent:      colorSpec{Light: "#00DFA2", Dark: "#3EC5F8"},
+		ToolbarText: "#FFFFFF",
+		HealthLow:   "#FF3E41",
+		HealthHigh:  "#00FF00",
+		Border:      "rounded",
+		Glyphs: map[string]string{
+			"@": "#FFD700",
+			"#": "#8A8A8A",
+			".": "#4E4E4E",
+			"+": "#AF8700",
+			">": "#FFFFFF",
+			"<": "#FFFFFF",
+			"!": "#FF5FD7",
+			"$": "#FFD700",
+			"g": "#5FD700",
+		},
+	}
+}
+
+func (s ThemeSpec) border() gloss.Border {
+	switch s.Border {
+	case "normal":
+		return gloss.NormalBorder()
+	case "thick":
+		return gloss.ThickBorder()
+	case "double":
+		return gloss.DoubleBorder()
+	case "hidden":
+		return gloss.HiddenBorder()
+	default:
+		return gloss.RoundedBorder()
+	}
+}
+
+// validate checks every color and the border name, reporting all problems at once
+func (s ThemeSpec) validate() error {
+	var problems []error
+	check := func(field, value string) {
+		if !colorPattern.MatchString(value) {
+			problems = append(problems, fmt.Errorf("%s: invalid color %q", field, value))
+		}
+	}
+	for field, c := range map[string]colorSpec{
+		"primary": s.Primary, "secondary": s.Secondary, "error": s.Error, "selected": s.Selected, "accent": s.Accent,
+	} {
+		check(field+".light", c.Light)
+		check(field+".dark", c.Dark)
+	}
+	check("toolbarText", s.ToolbarText)
+	check("healthLow", s.HealthLow)
+	check("healthHigh", s.HealthHigh)
+	for glyph, color := range s.Glyphs {
+		check("glyphs."+glyph, color)
+	}
+	switch s.Border {
+	case "", "rounded", "normal", "thick", "double", "hidden":
+	default:
+		problems = append(problems, fmt.Errorf("border: unknown style %q", s.Border))
+	}
+	return errors.Join(problems...)
+}
+
+// parseThemeSpec reads a theme file on top of the default theme, so files only need the colors they change
+func parseThemeSpec(data []byte, fallbackName string) (ThemeSpec, error) {
+	spec := defaultThemeSpec()
+	spec.Name = ""
+	if err := json.Unmarshal(data, &spec); err != nil {
+		return ThemeSpec{}, err
+	}
+	if spec.Name == "" {
+		spec.Name = fallbackName
+	}
+	if err := spec.validate(); err != nil {
+		return ThemeSpec{}, err
+	}
+	return spec, nil
+}
+
+// themeSource is a theme that can be picked, with the user file it came from if any
+type themeSource struct {
+	spec ThemeSpec
+	path string // Empty for built-in themes
+}
+
+// userThemeDir returns the directory players put their own theme files in
+func userThemeDir() (string, error) {
+	saveDir, err := getSaveDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(saveDir, themeDirName), nil
+}
+
+// availableThemes returns the built-in themes followed by mod themes and then user themes,
+// each replacing earlier themes of the same name
+func availableThemes(mods []modManifest) ([]themeSource, error) {
+	themes := []themeSource{{spec: defaultThemeSpec()}}
+	entries, err := builtinThemes.ReadDir(themeDirName)
+	if err != nil {
+		return themes, err
+	}
+	for _, entry := range entries {
+		data, err := builtinThemes.ReadFile(themeDirName + "/" + entry.Name())
+		if err != nil {
+			return themes, err
+		}
+		spec, err := parseThemeSpec(data, strings.TrimSuffix(entry.Name(), ".json"))
+		if err != nil {
+			return themes, fmt.Errorf("built-in theme %s: %w", entry.Name(), err)
+		}
+		themes = append(themes, themeSource{spec: spec})
+	}
+
+	var problems []error
+	for _, mod := range mods {
+		for _, path := range mod.modFiles(themeDirName) {
+			source, err := readUserTheme(path)
+			if err != nil {
+				problems = append(problems, fmt.Errorf("mod %s: %w", mod.Name, err))
+				continue
+			}
+			themes = replaceTheme(themes, source)
+		}
+	}
+
+	dir, err := userThemeDir()
+	if err != nil {
+		return themes, err
+	}
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return themes, err
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		source, err := readUserTheme(path)
+		if err != nil {
+			problems = append(problems, err)
+			continue
+		}
+		themes = replaceTheme(themes, source)
+	}
+	return themes, errors.Join(problems...)
+}
+
+func readUserTheme(path string) (themeSource, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return themeSource{}, err
+	}
+	spec, err := parseThemeSpec(data, strings.TrimSuffix(filepath.Base(path), ".json"))
+	if err != nil {
+		return themeSource{}, fmt.Errorf("theme %s: %w", filepath.Base(path), err)
+	}
+	return themeSource{spec: spec, path: path}, nil
+}
+
+func replaceTheme(themes []themeSource, source themeSource) []themeSource {
+	for i, existing := range themes {
+		if strings.EqualFold(existing.spec.Name, source.spec.Name) {
+			themes[i] = source
+			return themes
+		}
+	}
+	return append(themes, source)
+}
+
+// findTheme returns the named theme, or the default if it doesn't exist.
+// Broken files for other themes don't matter as long as the named one loads.
+func findTheme(name string, mods []modManifest) (themeSource, error) {
+	themes, err := availableThemes(mods)
+	for _, source := range themes {
+		if strings.EqualFold(source.spec.Name, name) {
+			return source, nil
+		}
+	}
+	return themes[0], errors.Join(fmt.Errorf("theme %q not found", name), err)
+}
+
+// applyTheme makes a theme the active one
+func (m *model) applyTheme(source themeSource) {
+	m.theme = newThemeFromSpec(source.spec)
+	m.progress = m.theme.ProgressBar
+	m.themePath = source.path
+	m.themeModTime = time.Time{}
+	if info, err := os.Stat(source.path); source.path != "" && err == nil {
+		m.themeModTime = info.ModTime()
+	}
+}
+
+// themeWatchMsg prompts a check for changes to the active theme file
+type themeWatchMsg struct{}
+
+func watchTheme() tea.Cmd {
+	return tea.Tick(themeWatchInterval, func(time.Time) tea.Msg {
+		return themeWatchMsg{}
+	})
+}
+
+// reloadThemeIfChanged re-reads the active user theme when its file has been modified
+func (m *model) reloadThemeIfChanged() tea.Cmd {
+	if m.themePath == "" {
+		return nil
+	}
+	info, err := os.Stat(m.themePath)
+	if err != nil || !info.ModTime().After(m.themeModTime) {
+		return nil
+	}
+	m.themeModTime = info.ModTime()
+	source, err := readUserTheme(m.themePath)
+	if err != nil {
+		// Keep the current look while the file is being edited into a valid state
+		return errorCmd(newGameError(errorInternal, "Could not reload theme", err))
+	}
+	m.theme = newThemeFromSpec(source.spec)
+	m.progress = m.theme.ProgressBar
+	return nil
+}